@@ -0,0 +1,383 @@
+// Package breakerbrick provides a circuit breaker to stop calling a degraded dependency.
+package breakerbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// State is a state of the circuit breaker.
+type State int
+
+const (
+	// StateClosed lets all the calls through and counts failures.
+	StateClosed State = iota
+	// StateHalfOpen lets a limited number of trial calls through to check if the dependency recovered.
+	StateHalfOpen
+	// StateOpen rejects all the calls with errbrick.ErrCircuitOpen.
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Option is a function that configures the breaker.
+type Option func(*breakerOpts)
+
+type breakerOpts struct {
+	IsFailure             func(err error) bool
+	Window                time.Duration
+	Buckets               int
+	MinCalls              int
+	FailureRateThreshold  float64
+	SlowCallDuration      time.Duration
+	SlowCallRateThreshold float64
+	OpenTimeout           time.Duration
+	HalfOpenCalls         int
+}
+
+// WithWindow sets the sliding window to compute the failure and slow call rates in.
+// The window is split into the buckets, the oldest bucket is dropped when the window slides.
+// 60s window with 10 buckets is used by default.
+func WithWindow(window time.Duration, buckets int) Option {
+	return func(opts *breakerOpts) {
+		opts.Window = window
+		opts.Buckets = buckets
+	}
+}
+
+// WithMinCalls sets the min number of calls in the window to compute the rates.
+// 20 is used by default.
+func WithMinCalls(n int) Option {
+	return func(opts *breakerOpts) {
+		opts.MinCalls = n
+	}
+}
+
+// WithFailureRateThreshold sets the failure rate (0..1) to open the circuit at.
+// 0.5 is used by default.
+func WithFailureRateThreshold(rate float64) Option {
+	return func(opts *breakerOpts) {
+		opts.FailureRateThreshold = rate
+	}
+}
+
+// WithSlowCallThreshold sets the duration of the call to be considered slow and the slow call rate (0..1) to open the circuit at.
+// The slow calls aren't tracked by default.
+func WithSlowCallThreshold(d time.Duration, rate float64) Option {
+	return func(opts *breakerOpts) {
+		opts.SlowCallDuration = d
+		opts.SlowCallRateThreshold = rate
+	}
+}
+
+// WithOpenTimeout sets the duration to keep the circuit open before switching to half-open.
+// 30s is used by default.
+func WithOpenTimeout(d time.Duration) Option {
+	return func(opts *breakerOpts) {
+		opts.OpenTimeout = d
+	}
+}
+
+// WithHalfOpenCalls sets the number of trial calls in half-open state.
+// The circuit is closed when all of them succeed and opened again on the first failure.
+// 5 is used by default.
+func WithHalfOpenCalls(n int) Option {
+	return func(opts *breakerOpts) {
+		opts.HalfOpenCalls = n
+	}
+}
+
+// WithFailurePredicate sets the function to decide if the call error is a failure.
+// By default, all errors except context.Canceled are failures.
+func WithFailurePredicate(f func(err error) bool) Option {
+	return func(opts *breakerOpts) {
+		opts.IsFailure = f
+	}
+}
+
+type bucket struct {
+	start    time.Time
+	calls    int
+	failures int
+	slow     int
+}
+
+// Breaker is a circuit breaker with closed, open and half-open states
+// and sliding window failure rate and slow call rate thresholds.
+type Breaker struct {
+	openedAt         time.Time
+	now              func() time.Time
+	transitions      metric.Int64Counter
+	rejected         metric.Int64Counter
+	registration     metric.Registration
+	buckets          []bucket
+	name             string
+	opts             breakerOpts
+	mu               sync.Mutex
+	state            State
+	generation       uint64
+	halfOpenInFlight int
+	halfOpenSuccess  int
+}
+
+// New creates a new Breaker. The name is used in errors, logs and metrics.
+// It returns an error if the options are invalid, e.g. the window is shorter than the number of buckets in ns,
+// the rate thresholds aren't in (0, 1] or the min and half-open calls aren't positive.
+// Call Close when the breaker isn't needed anymore to unregister its metrics callback.
+func New(name string, options ...Option) (*Breaker, error) {
+	opts := breakerOpts{
+		IsFailure: func(err error) bool {
+			return err != nil && !errors.Is(err, context.Canceled)
+		},
+		Window:               time.Minute,
+		Buckets:              10,
+		MinCalls:             20,
+		FailureRateThreshold: 0.5,
+		OpenTimeout:          30 * time.Second,
+		HalfOpenCalls:        5,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	b := &Breaker{
+		name:    name,
+		opts:    opts,
+		now:     time.Now,
+		buckets: make([]bucket, opts.Buckets),
+	}
+	if err := b.initMetrics(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (opts breakerOpts) validate() error {
+	if opts.Buckets < 1 {
+		return fmt.Errorf("invalid circuit breaker buckets %d: must be positive", opts.Buckets)
+	}
+	if opts.Window < time.Duration(opts.Buckets) {
+		return fmt.Errorf("invalid circuit breaker window %s: must be at least 1ns per bucket", opts.Window)
+	}
+	if opts.MinCalls < 1 {
+		return fmt.Errorf("invalid circuit breaker min calls %d: must be positive", opts.MinCalls)
+	}
+	if opts.HalfOpenCalls < 1 {
+		return fmt.Errorf("invalid circuit breaker half-open calls %d: must be positive", opts.HalfOpenCalls)
+	}
+	if opts.FailureRateThreshold <= 0 || opts.FailureRateThreshold > 1 {
+		return fmt.Errorf("invalid circuit breaker failure rate threshold %v: must be in (0, 1]", opts.FailureRateThreshold)
+	}
+	if opts.SlowCallDuration > 0 && (opts.SlowCallRateThreshold <= 0 || opts.SlowCallRateThreshold > 1) {
+		return fmt.Errorf("invalid circuit breaker slow call rate threshold %v: must be in (0, 1]", opts.SlowCallRateThreshold)
+	}
+	return nil
+}
+
+func (b *Breaker) initMetrics() error {
+	meter := otel.GetMeterProvider().Meter("bricks/breakerbrick")
+	var err error
+	b.transitions, err = meter.Int64Counter("circuit_breaker.transitions",
+		metric.WithDescription("The number of circuit breaker state transitions."))
+	if err != nil {
+		return fmt.Errorf("failed create circuit_breaker.transitions metric: %w", err)
+	}
+	b.rejected, err = meter.Int64Counter("circuit_breaker.rejected_calls",
+		metric.WithDescription("The number of calls rejected by the open circuit breaker."))
+	if err != nil {
+		return fmt.Errorf("failed create circuit_breaker.rejected_calls metric: %w", err)
+	}
+	state, err := meter.Int64ObservableGauge("circuit_breaker.state",
+		metric.WithDescription("The state of the circuit breaker (0 is closed, 1 is half-open, 2 is open)."))
+	if err != nil {
+		return fmt.Errorf("failed create circuit_breaker.state metric: %w", err)
+	}
+	b.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(state, int64(b.State()), metric.WithAttributes(attribute.String("circuit_breaker.name", b.name)))
+		return nil
+	}, state)
+	if err != nil {
+		return fmt.Errorf("failed register circuit_breaker.state callback: %w", err)
+	}
+	return nil
+}
+
+// Close unregisters the circuit_breaker.state metric callback, so the breaker can be garbage collected.
+// The breaker keeps working after Close, but its state isn't reported anymore.
+func (b *Breaker) Close() error {
+	if err := b.registration.Unregister(); err != nil {
+		return fmt.Errorf("failed unregister circuit_breaker.state callback: %w", err)
+	}
+	return nil
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refreshState()
+	return b.state
+}
+
+// Allow checks if the call is allowed. If it's not, the error wrapping errbrick.ErrCircuitOpen is returned.
+// Otherwise, the returned func must be called with the call result (error or nil) when the call is finished.
+func (b *Breaker) Allow(ctx context.Context) (func(err error), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refreshState()
+
+	switch {
+	case b.state == StateOpen,
+		b.state == StateHalfOpen && b.halfOpenInFlight+b.halfOpenSuccess >= b.opts.HalfOpenCalls:
+		b.rejected.Add(ctx, 1, metric.WithAttributes(attribute.String("circuit_breaker.name", b.name)))
+		return nil, fmt.Errorf("%w: %s", errbrick.ErrCircuitOpen, b.name)
+	case b.state == StateHalfOpen:
+		b.halfOpenInFlight++
+	}
+
+	generation := b.generation
+	startTime := b.now()
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			b.record(ctx, generation, b.opts.IsFailure(err), b.now().Sub(startTime))
+		})
+	}, nil
+}
+
+// Execute calls fn if the breaker allows and records its result.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := b.Allow(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	done(err)
+	return err
+}
+
+func (b *Breaker) record(ctx context.Context, generation uint64, failure bool, duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		// the result of the call started before the last state transition
+		return
+	}
+
+	if b.state == StateHalfOpen {
+		b.halfOpenInFlight--
+		if failure {
+			b.transition(ctx, StateOpen)
+			return
+		}
+		b.halfOpenSuccess++
+		if b.halfOpenSuccess >= b.opts.HalfOpenCalls {
+			b.transition(ctx, StateClosed)
+		}
+		return
+	}
+
+	bkt := b.currentBucket()
+	bkt.calls++
+	if failure {
+		bkt.failures++
+	}
+	slowCall := b.opts.SlowCallDuration > 0 && duration >= b.opts.SlowCallDuration
+	if slowCall {
+		bkt.slow++
+	}
+	if b.exceedsThresholds() {
+		b.transition(ctx, StateOpen)
+	}
+}
+
+func (b *Breaker) exceedsThresholds() bool {
+	var calls, failures, slow int
+	windowStart := b.now().Add(-b.opts.Window)
+	for _, bkt := range b.buckets {
+		if bkt.start.Before(windowStart) {
+			continue
+		}
+		calls += bkt.calls
+		failures += bkt.failures
+		slow += bkt.slow
+	}
+	if calls == 0 || calls < b.opts.MinCalls {
+		return false
+	}
+	if float64(failures)/float64(calls) >= b.opts.FailureRateThreshold {
+		return true
+	}
+	return b.opts.SlowCallDuration > 0 && float64(slow)/float64(calls) >= b.opts.SlowCallRateThreshold
+}
+
+func (b *Breaker) currentBucket() *bucket {
+	bucketSize := b.opts.Window / time.Duration(len(b.buckets))
+	now := b.now()
+	start := now.Truncate(bucketSize)
+	idx := int((start.UnixNano() / int64(bucketSize)) % int64(len(b.buckets)))
+	bkt := &b.buckets[idx]
+	if !bkt.start.Equal(start) {
+		*bkt = bucket{start: start}
+	}
+	return bkt
+}
+
+// refreshState switches the open circuit to half-open when the open timeout passes.
+func (b *Breaker) refreshState() {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		b.transition(context.Background(), StateHalfOpen)
+	}
+}
+
+func (b *Breaker) transition(ctx context.Context, to State) {
+	from := b.state
+	b.state = to
+	b.generation++
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccess = 0
+	switch to {
+	case StateOpen:
+		b.openedAt = b.now()
+	case StateClosed:
+		for i := range b.buckets {
+			b.buckets[i] = bucket{}
+		}
+	case StateHalfOpen:
+	}
+
+	b.transitions.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("circuit_breaker.name", b.name),
+		attribute.String("circuit_breaker.from", from.String()),
+		attribute.String("circuit_breaker.to", to.String())))
+	lvl := slog.LevelInfo
+	if to == StateOpen {
+		lvl = slog.LevelWarn
+	}
+	slogbrick.FromCtx(ctx).Log(ctx, lvl, "circuit breaker state changed",
+		slog.String("circuit_breaker", b.name), slog.String("from", from.String()), slog.String("to", to.String()))
+}
@@ -0,0 +1,192 @@
+package breakerbrick
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Add(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(t *testing.T, options ...Option) (*Breaker, *clock) {
+	t.Helper()
+	b, err := New("test", options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = b.Close() })
+	clk := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b.now = clk.Now
+	return b, clk
+}
+
+func call(b *Breaker, err error) error {
+	return b.Execute(context.Background(), func(context.Context) error { return err })
+}
+
+var errDependency = errors.New("dependency failed")
+
+func TestBreaker_OpensOnFailureRate(t *testing.T) {
+	b, _ := newTestBreaker(t, WithMinCalls(4), WithFailureRateThreshold(0.5))
+
+	require.NoError(t, call(b, nil))
+	require.NoError(t, call(b, nil))
+	require.ErrorIs(t, call(b, errDependency), errDependency)
+	assert.Equal(t, StateClosed, b.State())
+	require.ErrorIs(t, call(b, errDependency), errDependency)
+	assert.Equal(t, StateOpen, b.State())
+
+	err := call(b, nil)
+	assert.ErrorIs(t, err, errbrick.ErrCircuitOpen)
+	assert.ErrorIs(t, err, errbrick.ErrUnavailable)
+}
+
+func TestBreaker_IgnoresCanceled(t *testing.T) {
+	b, _ := newTestBreaker(t, WithMinCalls(2))
+
+	for i := 0; i < 5; i++ {
+		_ = call(b, context.Canceled)
+	}
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_OpensOnSlowCallRate(t *testing.T) {
+	b, clk := newTestBreaker(t, WithMinCalls(2), WithSlowCallThreshold(time.Second, 0.5))
+
+	for i := 0; i < 2; i++ {
+		err := b.Execute(context.Background(), func(context.Context) error {
+			clk.Add(2 * time.Second)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_WindowSlides(t *testing.T) {
+	b, clk := newTestBreaker(t, WithMinCalls(2), WithWindow(10*time.Second, 10))
+
+	_ = call(b, errDependency)
+	clk.Add(11 * time.Second)
+	_ = call(b, errDependency)
+	assert.Equal(t, StateClosed, b.State(), "the first failure is out of the window")
+	_ = call(b, errDependency)
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   []error
+		wantState State
+	}{
+		{name: "recovered", results: []error{nil, nil}, wantState: StateClosed},
+		{name: "still-failing", results: []error{nil, errDependency}, wantState: StateOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clk := newTestBreaker(t, WithMinCalls(1), WithOpenTimeout(time.Second), WithHalfOpenCalls(2))
+			_ = call(b, errDependency)
+			require.Equal(t, StateOpen, b.State())
+
+			clk.Add(time.Second)
+			require.Equal(t, StateHalfOpen, b.State())
+
+			done1, err := b.Allow(context.Background())
+			require.NoError(t, err)
+			done2, err := b.Allow(context.Background())
+			require.NoError(t, err)
+			_, err = b.Allow(context.Background())
+			require.ErrorIs(t, err, errbrick.ErrCircuitOpen, "only the configured number of trial calls is allowed")
+
+			done1(tt.results[0])
+			done2(tt.results[1])
+			assert.Equal(t, tt.wantState, b.State())
+		})
+	}
+}
+
+func TestBreaker_IgnoresStaleResults(t *testing.T) {
+	b, _ := newTestBreaker(t, WithMinCalls(1))
+
+	done, err := b.Allow(context.Background())
+	require.NoError(t, err)
+	_ = call(b, errDependency)
+	require.Equal(t, StateOpen, b.State())
+
+	done(nil)
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_Close(t *testing.T) {
+	prevProvider := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(prevProvider) })
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	b, err := New("test")
+	require.NoError(t, err)
+	collectState := func() bool {
+		rm := metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == "circuit_breaker.state" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	assert.True(t, collectState())
+	require.NoError(t, b.Close())
+	assert.False(t, collectState())
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		wantErr bool
+	}{
+		{name: "New_WithDefaults_Succeeds"},
+		{name: "New_WithMinWindow_Succeeds", options: []Option{WithWindow(10, 10)}},
+		{name: "New_WithZeroWindow_Fails", options: []Option{WithWindow(0, 10)}, wantErr: true},
+		{name: "New_WithWindowShorterThanBuckets_Fails", options: []Option{WithWindow(9, 10)}, wantErr: true},
+		{name: "New_WithZeroBuckets_Fails", options: []Option{WithWindow(time.Minute, 0)}, wantErr: true},
+		{name: "New_WithZeroMinCalls_Fails", options: []Option{WithMinCalls(0)}, wantErr: true},
+		{name: "New_WithZeroHalfOpenCalls_Fails", options: []Option{WithHalfOpenCalls(0)}, wantErr: true},
+		{name: "New_WithNegativeHalfOpenCalls_Fails", options: []Option{WithHalfOpenCalls(-1)}, wantErr: true},
+		{name: "New_WithZeroFailureRate_Fails", options: []Option{WithFailureRateThreshold(0)}, wantErr: true},
+		{name: "New_WithFailureRateAboveOne_Fails", options: []Option{WithFailureRateThreshold(1.5)}, wantErr: true},
+		{name: "New_WithFailureRateOne_Succeeds", options: []Option{WithFailureRateThreshold(1)}},
+		{name: "New_WithZeroSlowCallRate_Fails", options: []Option{WithSlowCallThreshold(time.Second, 0)}, wantErr: true},
+		{name: "New_WithSlowCallRateAboveOne_Fails", options: []Option{WithSlowCallThreshold(time.Second, 2)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New("test", tt.options...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer b.Close()
+			assert.NoError(t, call(b, nil))
+		})
+	}
+}
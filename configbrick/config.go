@@ -58,10 +58,11 @@ type HTTP struct {
 
 // GRPC represents the gRPC server configuration.
 type GRPC struct {
-	AccessLogLevel   string `default:"debug" split_words:"true" json:"access_log_level"`
-	Port             int    `required:"true" json:"port"`
-	AccessLog        bool   `split_words:"true" json:"access_log"`
-	EnableReflection bool   `default:"true" split_words:"true" json:"enable_reflection"`
+	AccessLogLevel   string        `default:"debug" split_words:"true" json:"access_log_level"`
+	Port             int           `required:"true" json:"port"`
+	ShutdownTimeout  time.Duration `default:"10s" split_words:"true" json:"shutdown_timeout"`
+	AccessLog        bool          `split_words:"true" json:"access_log"`
+	EnableReflection bool          `default:"true" split_words:"true" json:"enable_reflection"`
 }
 
 // Redis represents the Redis configuration.
@@ -100,10 +101,33 @@ type Cassandra struct {
 
 // OTEL represents the OpenTelemetry configuration.
 type OTEL struct {
+	// Propagators are the comma-separated names of the propagators (tracecontext, baggage, b3, b3multi, jaeger),
+	// or "none" alone to disable the propagation.
+	// See otelbrick.NewPropagator.
+	Propagators []string `default:"tracecontext,baggage" json:"propagators"`
 	// Meter represents the OpenTelemetry meter configuration.
 	Meter OTLP `json:"meter"`
 	// Trace represents the OpenTelemetry trace configuration.
 	Trace OTLP `json:"trace"`
+	// Sampling represents the OpenTelemetry trace sampling configuration.
+	Sampling Sampling `json:"sampling"`
+}
+
+// Sampling represents the trace sampling configuration (see otelbrick.TraceConfig).
+type Sampling struct {
+	// Rules are the semicolon-separated per span name or route rules in "pattern=rate" format,
+	// e.g. "^GET /health=0;/orders/[0-9]{1,3}=0.5" (see otelbrick.ParseSamplingRules).
+	// The semicolon is used as the separator because the comma may appear in the patterns.
+	Rules string `json:"rules"`
+	// DebugHeader is the HTTP header forcing the sampling of the request when it's non-empty, e.g. X-Debug-Trace.
+	// Pass it to httpbrick.WithTraceDebugHeader (e.g. via httpbrick.WithServerTraceOpts). Empty disables it.
+	DebugHeader string `json:"debug_header" split_words:"true"`
+	// Rate is the ratio of the sampled traces. 0 samples all traces.
+	Rate float64 `json:"rate"`
+	// RateLimit is the max number of the sampled traces per second. 0 disables the limit.
+	RateLimit float64 `json:"rate_limit" split_words:"true"`
+	// OnError exports the traces with errors regardless of the sampling.
+	OnError bool `json:"on_error" split_words:"true"`
 }
 
 type OTLP struct {
@@ -0,0 +1,55 @@
+package cqlbrick
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gocql/gocql"
+
+	"github.com/demeero/bricks/breakerbrick"
+)
+
+// Breaker protects the CQL queries with the circuit breaker.
+// gocql.ErrNotFound isn't recorded as a failure.
+// If the circuit is open, the query isn't executed and the error wrapping errbrick.ErrCircuitOpen is returned.
+type Breaker struct {
+	breaker *breakerbrick.Breaker
+}
+
+// NewBreaker creates a new Breaker.
+func NewBreaker(breaker *breakerbrick.Breaker) *Breaker {
+	return &Breaker{breaker: breaker}
+}
+
+// Exec executes the query (see gocql.Query.Exec).
+func (b *Breaker) Exec(q *gocql.Query) error {
+	return b.execute(q.Context(), q.Exec)
+}
+
+// Scan executes the query and scans the first row (see gocql.Query.Scan).
+func (b *Breaker) Scan(q *gocql.Query, dest ...interface{}) error {
+	return b.execute(q.Context(), func() error {
+		return q.Scan(dest...)
+	})
+}
+
+// ExecuteBatch executes the batch (see gocql.Session.ExecuteBatch).
+func (b *Breaker) ExecuteBatch(session *gocql.Session, batch *gocql.Batch) error {
+	return b.execute(batch.Context(), func() error {
+		return session.ExecuteBatch(batch)
+	})
+}
+
+func (b *Breaker) execute(ctx context.Context, fn func() error) error {
+	done, err := b.breaker.Allow(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if errors.Is(err, gocql.ErrNotFound) {
+		done(nil)
+	} else {
+		done(err)
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package cqlbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+// SessionChecker returns healthbrick.Checker that queries the local node via the session.
+func SessionChecker(session *gocql.Session) healthbrick.Checker {
+	return func(ctx context.Context) error {
+		if session.Closed() {
+			return errors.New("cql session is closed")
+		}
+		var releaseVersion string
+		err := session.Query("SELECT release_version FROM system.local").WithContext(ctx).Scan(&releaseVersion)
+		if err != nil {
+			return fmt.Errorf("failed query system.local: %w", err)
+		}
+		return nil
+	}
+}
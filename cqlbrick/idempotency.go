@@ -0,0 +1,94 @@
+package cqlbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/demeero/bricks/idempotencybrick"
+)
+
+// IdempotencyStore is idempotencybrick.Store backed by Cassandra.
+// All queries are lightweight transactions, so the concurrent duplicates are locked across the instances.
+// The table must have the following schema:
+//
+//	CREATE TABLE idempotency_keys (
+//		key text PRIMARY KEY,
+//		fingerprint text,
+//		completed boolean,
+//		status int,
+//		headers map<text, frozen<list<text>>>,
+//		body blob
+//	);
+type IdempotencyStore struct {
+	session *gocql.Session
+	table   string
+}
+
+// NewIdempotencyStore creates a new IdempotencyStore that stores the records in the table.
+func NewIdempotencyStore(session *gocql.Session, table string) *IdempotencyStore {
+	return &IdempotencyStore{session: session, table: table}
+}
+
+// Lock implements idempotencybrick.Store.
+func (s *IdempotencyStore) Lock(ctx context.Context, key, fingerprint string, ttl time.Duration) (idempotencybrick.Record, bool, error) {
+	existing := make(map[string]interface{})
+	applied, err := s.session.Query(
+		"INSERT INTO "+s.table+" (key, fingerprint, completed) VALUES (?, ?, false) IF NOT EXISTS USING TTL ?",
+		key, fingerprint, ttlSeconds(ttl)).
+		WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return idempotencybrick.Record{}, false, fmt.Errorf("failed insert idempotency key: %w", err)
+	}
+	if applied {
+		return idempotencybrick.Record{Fingerprint: fingerprint}, true, nil
+	}
+	return recordFromRow(existing), false, nil
+}
+
+// Save implements idempotencybrick.Store.
+// It fails if the lock has already expired or the key is locked by another in-progress request.
+func (s *IdempotencyStore) Save(ctx context.Context, key string, rec idempotencybrick.Record, ttl time.Duration) error {
+	applied, err := s.session.Query(
+		"UPDATE "+s.table+" USING TTL ? SET fingerprint = ?, completed = ?, status = ?, headers = ?, body = ? WHERE key = ? IF fingerprint = ? AND completed = false",
+		ttlSeconds(ttl), rec.Fingerprint, rec.Completed, rec.Status, map[string][]string(rec.Header), rec.Body, key, rec.Fingerprint).
+		WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("failed update idempotency key: %w", err)
+	}
+	if !applied {
+		return errors.New("idempotency key lock expired")
+	}
+	return nil
+}
+
+// Unlock implements idempotencybrick.Store.
+func (s *IdempotencyStore) Unlock(ctx context.Context, key string) error {
+	_, err := s.session.Query("DELETE FROM "+s.table+" WHERE key = ? IF EXISTS", key).
+		WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("failed delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func recordFromRow(row map[string]interface{}) idempotencybrick.Record {
+	rec := idempotencybrick.Record{}
+	rec.Fingerprint, _ = row["fingerprint"].(string)
+	rec.Completed, _ = row["completed"].(bool)
+	rec.Status, _ = row["status"].(int)
+	rec.Body, _ = row["body"].([]byte)
+	if headers, ok := row["headers"].(map[string][]string); ok {
+		rec.Header = http.Header(headers)
+	}
+	return rec
+}
+
+// ttlSeconds converts ttl to CQL TTL, which must be at least 1 second.
+func ttlSeconds(ttl time.Duration) int {
+	return max(1, int(ttl/time.Second))
+}
@@ -122,7 +122,7 @@ func (o OTELMeterQueryObserver) ObserveQuery(ctx context.Context, q gocql.Observ
 	if o.Disabled {
 		return
 	}
-	attrs := append(otelbrick.AttrsFromCtx(ctx), semconv.DBSystemCassandra)
+	attrs := append(otelbrick.MetricAttrsFromCtx(ctx), semconv.DBSystemCassandra)
 	if q.Err != nil && !errors.Is(q.Err, gocql.ErrNotFound) {
 		attrs = append(attrs, semconv.OTelStatusCodeError)
 	} else {
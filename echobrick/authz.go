@@ -0,0 +1,21 @@
+package echobrick
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+// AuthorizeMW is a middleware that checks the token claims against the policies (see jwtbrick.Authorize).
+// It's expected that the claims are put to the request context by TokenClaimsMW.
+// Errors are returned as is, so ErrorHandler maps them to 401/403.
+func AuthorizeMW(policies ...jwtbrick.Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := jwtbrick.Authorize(c.Request().Context(), policies...); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
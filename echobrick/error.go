@@ -4,9 +4,9 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/demeero/bricks/errbrick"
@@ -18,30 +18,26 @@ import (
 type FallbackFunc func(err error) *echo.HTTPError
 
 // ErrorHandler returns an error handler for echo.
+// errbrick errors are mapped to HTTP statuses via the errbrick registry (see errbrick.Register).
 // If fallback is not nil, it will be called if the error is not recognized.
 // If fallback returns nil, the error will be handled as an internal server error.
+// If a recognized error is errbrick.DetailedError, the response body is errbrick.ErrorBody.
 func ErrorHandler(fallback FallbackFunc) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		if c.Response().Committed {
 			return
 		}
 		var (
-			lg      = slogbrick.FromCtx(c.Request().Context())
-			echoErr *echo.HTTPError
+			lg              = slogbrick.FromCtx(c.Request().Context())
+			mapping, mapped = errbrick.Lookup(err)
+			echoErr         *echo.HTTPError
 		)
 		switch {
 		case errors.As(err, &echoErr):
 			handleEchoErr(echoErr, lg)
-		case errors.Is(err, errbrick.ErrInvalidData):
-			echoErr = echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		case errors.Is(err, errbrick.ErrNotFound):
-			echoErr = echo.NewHTTPError(http.StatusNotFound, err.Error())
-		case errors.Is(err, errbrick.ErrForbidden):
-			echoErr = echo.NewHTTPError(http.StatusForbidden, err.Error())
-		case errors.Is(err, errbrick.ErrConflict):
-			echoErr = echo.NewHTTPError(http.StatusConflict, err.Error())
-		case errors.Is(err, errbrick.ErrUnauthenticated):
-			echoErr = echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			mapped = false
+		case mapped:
+			echoErr = echo.NewHTTPError(mapping.HTTPStatus, err.Error())
 		default:
 			if fallback != nil {
 				if fallbackErr := fallback(err); fallbackErr != nil {
@@ -52,28 +48,34 @@ func ErrorHandler(fallback FallbackFunc) echo.HTTPErrorHandler {
 			lg.Error("internal server err", slog.Any("err", err))
 			echoErr = echo.NewHTTPError(http.StatusInternalServerError)
 		}
-		if err = c.JSON(echoErr.Code, echoErr); err != nil {
+		if err = c.JSON(echoErr.Code, errRespBody(c, err, echoErr, mapped)); err != nil {
 			lg.Error("failed send err resp", slog.Any("err", err))
 		}
 	}
 }
 
 // GRPCErrFallback maps grpc errors to echo http errors.
+// The mapping is driven by the errbrick registry (see errbrick.Register).
 func GRPCErrFallback(err error) *echo.HTTPError {
 	grpcStatus := status.Convert(err)
-	switch grpcStatus.Code() {
-	case codes.InvalidArgument:
-		return echo.NewHTTPError(http.StatusBadRequest, grpcStatus.Message())
-	case codes.NotFound:
-		return echo.NewHTTPError(http.StatusNotFound, grpcStatus.Message())
-	case codes.PermissionDenied:
-		return echo.NewHTTPError(http.StatusForbidden, grpcStatus.Message())
-	case codes.AlreadyExists:
-		return echo.NewHTTPError(http.StatusConflict, grpcStatus.Message())
-	case codes.Unauthenticated:
-		return echo.NewHTTPError(http.StatusUnauthorized, grpcStatus.Message())
+	mapping, ok := errbrick.LookupGRPCCode(grpcStatus.Code())
+	if !ok {
+		return nil
 	}
-	return nil
+	return echo.NewHTTPError(mapping.HTTPStatus, grpcStatus.Message())
+}
+
+// errRespBody returns errbrick.ErrorBody if err is errbrick.DetailedError of a registered kind and echoErr otherwise.
+// Details of unknown (internal) errors are never exposed to the client.
+func errRespBody(c echo.Context, err error, echoErr *echo.HTTPError, mapped bool) interface{} {
+	if _, ok := errbrick.AsDetailed(err); !ok || !mapped {
+		return echoErr
+	}
+	body := errbrick.NewErrorBody(err)
+	if body.RetryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.FormatInt(body.RetryAfter, 10))
+	}
+	return body
 }
 
 func handleEchoErr(echoErr *echo.HTTPError, lg *slog.Logger) {
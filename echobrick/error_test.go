@@ -58,6 +58,37 @@ func TestErrorHandler(t *testing.T) {
 			expectedCode: http.StatusUnauthorized,
 			expectedBody: `{"message":"unauthenticated"}` + "\n",
 		},
+		{
+			name:         "HandleRateLimitedError",
+			err:          fmt.Errorf("%w: too many attempts", errbrick.ErrRateLimited),
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: `{"message":"rate limited: too many attempts"}` + "\n",
+		},
+		{
+			name:         "HandleUnavailableError",
+			err:          errbrick.ErrUnavailable,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"message":"unavailable"}` + "\n",
+		},
+		{
+			name:         "HandleCanceledError",
+			err:          errbrick.ErrCanceled,
+			expectedCode: errbrick.StatusClientClosedRequest,
+			expectedBody: `{"message":"canceled"}` + "\n",
+		},
+		{
+			name: "HandleDetailedError",
+			err: errbrick.NewDetailed(errbrick.ErrInvalidData, "invalid user",
+				errbrick.WithReason("USER_INVALID"), errbrick.WithFieldViolation("email", "required")),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"message":"invalid data: invalid user","reason":"USER_INVALID","violations":[{"field":"email","description":"required"}]}` + "\n",
+		},
+		{
+			name:         "HandleDetailedUnknownError",
+			err:          errbrick.NewDetailed(nil, "secret", errbrick.WithReason("DB_DOWN")),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"message":"Internal Server Error"}` + "\n",
+		},
 		{
 			name:         "HandleUnknownError",
 			err:          assert.AnError,
@@ -116,6 +147,16 @@ func TestGRPCErrFallback(t *testing.T) {
 			err:      status.Error(codes.Unauthenticated, "unauthenticated"),
 			expected: echo.NewHTTPError(http.StatusUnauthorized, "unauthenticated"),
 		},
+		{
+			name:     "HandleUnavailableError",
+			err:      status.Error(codes.Unavailable, "unavailable"),
+			expected: echo.NewHTTPError(http.StatusServiceUnavailable, "unavailable"),
+		},
+		{
+			name:     "HandleFailedPreconditionError",
+			err:      status.Error(codes.FailedPrecondition, "already shipped"),
+			expected: echo.NewHTTPError(http.StatusPreconditionFailed, "already shipped"),
+		},
 		{
 			name:     "HandleUnknownError",
 			err:      status.Error(codes.Unknown, "unknown error"),
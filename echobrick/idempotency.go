@@ -0,0 +1,71 @@
+package echobrick
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/idempotencybrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// IdempotencyMW is an Echo variant of httpbrick.IdempotencyMW.
+// The anonymous requests (without the JWT subject) are handled as if they had no key.
+// The rejected requests are returned as errbrick.ErrConflict errors, so ErrorHandler responds with 409.
+// The panicking requests unlock the key, so they can be retried.
+// The handler errors are handled (see echo.Context.Error) inside the middleware to store the error responses.
+func IdempotencyMW(keeper *idempotencybrick.Keeper, skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+			req := c.Request()
+			sub, _ := TokenClaimsFromCtx(req.Context()).GetSubject()
+			key := keeper.Key(req, sub)
+			if key == "" {
+				return next(c)
+			}
+			fingerprint, err := idempotencybrick.Fingerprint(req)
+			if err != nil {
+				return errbrick.NewDetailed(errbrick.ErrInvalidData, err.Error())
+			}
+			rec, err := keeper.Begin(req.Context(), key, fingerprint)
+			if err != nil {
+				return err
+			}
+			if rec != nil {
+				return idempotencybrick.Replay(c.Response(), rec)
+			}
+
+			origWriter := c.Response().Writer
+			cw, capture := idempotencybrick.NewCapture(origWriter)
+			c.Response().Writer = cw
+			completed := false
+			defer func() {
+				// the handler panicked (see middleware.Recover), so unlock the key for the retries
+				if !completed {
+					c.Response().Writer = origWriter
+					rec := idempotencybrick.Record{Fingerprint: fingerprint, Status: http.StatusInternalServerError}
+					if err := keeper.Complete(context.WithoutCancel(req.Context()), key, rec); err != nil {
+						slogbrick.FromCtx(req.Context()).Error("failed unlock idempotency key", slog.Any("err", err))
+					}
+				}
+			}()
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			completed = true
+			c.Response().Writer = origWriter
+			// the response is already sent, so the client disconnect must not prevent storing it
+			if err := keeper.Complete(context.WithoutCancel(req.Context()), key, capture.Record(fingerprint)); err != nil {
+				slogbrick.FromCtx(req.Context()).Error("failed complete idempotent req", slog.Any("err", err))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package echobrick
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/idempotencybrick"
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestIdempotencyMW(t *testing.T) {
+	var calls int
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(nil)
+	e.Use(IdempotencyMW(idempotencybrick.NewKeeper(idempotencybrick.NewMemoryStore()), nil))
+	e.POST("/orders", func(c echo.Context) error {
+		calls++
+		return fmt.Errorf("%w: out of stock", errbrick.ErrPreconditionFailed)
+	})
+
+	serve := func(sub string) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		if sub != "" {
+			req = req.WithContext(jwtbrick.ClaimsToCtx(req.Context(), jwt.MapClaims{"sub": sub}))
+		}
+		req.Header.Set("Idempotency-Key", "k1")
+		rr := httptest.NewRecorder()
+		e.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Contains(t, rr.Body.String(), "out of stock")
+	}
+
+	serve("user-1")
+	serve("user-1")
+	assert.Equal(t, 1, calls)
+
+	// anonymous callers don't share the keys
+	serve("")
+	serve("")
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyMW_WithPanic_UnlocksKey(t *testing.T) {
+	var calls int
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(nil)
+	e.Use(middleware.Recover(), IdempotencyMW(idempotencybrick.NewKeeper(idempotencybrick.NewMemoryStore()), nil))
+	e.POST("/orders", func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return c.NoContent(http.StatusCreated)
+	})
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req = req.WithContext(jwtbrick.ClaimsToCtx(req.Context(), jwt.MapClaims{"sub": "user-1"}))
+		req.Header.Set("Idempotency-Key", "k1")
+		rr := httptest.NewRecorder()
+		e.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, http.StatusCreated, serve())
+	assert.Equal(t, 2, calls)
+}
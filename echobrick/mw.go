@@ -10,33 +10,41 @@ import (
 	"github.com/MicahParks/keyfunc/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/slogbrick"
 )
 
+// TokenClaimsMW is a middleware that extracts the JWT token from the request, validates it against JWKS
+// retrieved from jwksURL and puts the claims to the request context.
+// If the JWKS can't be loaded, the error is logged and the middleware rejects all requests.
+// Use TokenClaimsValidatorMW to validate tokens with static keys, HMAC secret, issuer, audience etc.
 func TokenClaimsMW(jwksURL string, opts keyfunc.Options) echo.MiddlewareFunc {
-	var jwtKeyFunc jwt.Keyfunc = func(token *jwt.Token) (interface{}, error) {
-		return jwt.UnsafeAllowNoneSignatureType, nil
-	}
-	if jwksURL != "" {
-		jwks, err := keyfunc.Get(jwksURL, opts)
-		if err != nil {
-			slog.Error("failed create JWKS from resource at the given URL",
-				slog.Any("err", err), slog.String("jwks_url", jwksURL))
-		} else {
-			jwtKeyFunc = jwks.Keyfunc
+	validator, err := jwtbrick.NewValidator(jwtbrick.WithJWKSURL(jwksURL), jwtbrick.WithKeyFuncOpts(opts))
+	if err != nil {
+		slog.Error("failed create JWT validator - all requests will be rejected",
+			slog.Any("err", err), slog.String("jwks_url", jwksURL))
+		return func(echo.HandlerFunc) echo.HandlerFunc {
+			return func(echo.Context) error {
+				return echo.NewHTTPError(http.StatusUnauthorized, "token validation is unavailable")
+			}
 		}
 	}
+	return TokenClaimsValidatorMW(validator)
+}
+
+// TokenClaimsValidatorMW is a middleware that extracts the JWT token from the request,
+// validates it with the validator and puts the claims to the request context.
+func TokenClaimsValidatorMW(validator *jwtbrick.Validator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			jwtToken, err := retrieveJWT(c.Request())
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, err)
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 			}
-			claims := jwt.MapClaims{}
-			tkn, err := jwt.ParseWithClaims(jwtToken, &claims, jwtKeyFunc)
+			claims, err := validator.Validate(jwtToken)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "failed parse token")
-			}
-			if !tkn.Valid {
+				slogbrick.FromCtx(c.Request().Context()).Debug("invalid token", slog.Any("err", err))
 				return echo.NewHTTPError(http.StatusUnauthorized, "invalid token")
 			}
 			c.SetRequest(c.Request().WithContext(tokenClaimsToCtx(c.Request().Context(), claims)))
@@ -45,20 +53,14 @@ func TokenClaimsMW(jwksURL string, opts keyfunc.Options) echo.MiddlewareFunc {
 	}
 }
 
-type jwtTokenClaimsKey struct{}
-
-var tknClaimsKey = jwtTokenClaimsKey{}
-
 func tokenClaimsToCtx(ctx context.Context, claims jwt.MapClaims) context.Context {
-	return context.WithValue(ctx, tknClaimsKey, claims)
+	return jwtbrick.ClaimsToCtx(ctx, claims)
 }
 
+// TokenClaimsFromCtx returns the token claims from the context.
+// It's a shortcut for jwtbrick.ClaimsFromCtx, use jwtbrick.TypedClaimsFromCtx to get typed claims.
 func TokenClaimsFromCtx(ctx context.Context) jwt.MapClaims {
-	claims, ok := ctx.Value(tknClaimsKey).(jwt.MapClaims)
-	if !ok {
-		return jwt.MapClaims{}
-	}
-	return claims
+	return jwtbrick.ClaimsFromCtx(ctx)
 }
 
 // retrieveJWT returns the token string from the request.
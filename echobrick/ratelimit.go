@@ -0,0 +1,55 @@
+package echobrick
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/demeero/bricks/ratelimitbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// RateLimitKeyFunc returns the key to limit the request by.
+type RateLimitKeyFunc func(c echo.Context) string
+
+// RateLimitKeyByIP returns the client IP (see echo.Context.RealIP).
+func RateLimitKeyByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// RateLimitKeyBySubject returns the JWT subject from the token claims (see TokenClaimsFromCtx).
+// It falls back to RateLimitKeyByIP for anonymous requests.
+func RateLimitKeyBySubject(c echo.Context) string {
+	if sub, err := TokenClaimsFromCtx(c.Request().Context()).GetSubject(); err == nil && sub != "" {
+		return "sub:" + sub
+	}
+	return RateLimitKeyByIP(c)
+}
+
+// RateLimitMW is an Echo variant of httpbrick.RateLimitMW.
+// The rejected requests are returned as errbrick.ErrRateLimited errors, so ErrorHandler responds with 429 and Retry-After.
+// If keyFunc is nil, RateLimitKeyByIP is used.
+func RateLimitMW(limiter ratelimitbrick.Limiter, keyFunc RateLimitKeyFunc, skipper middleware.Skipper) echo.MiddlewareFunc {
+	if keyFunc == nil {
+		keyFunc = RateLimitKeyByIP
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+			res, err := limiter.Allow(ctx, keyFunc(c))
+			if err != nil {
+				slogbrick.FromCtx(ctx).Error("failed check rate limit", slog.Any("err", err))
+				return next(c)
+			}
+			ratelimitbrick.SetHeaders(c.Response().Header(), res)
+			if !res.Allowed {
+				return res.Err()
+			}
+			return next(c)
+		}
+	}
+}
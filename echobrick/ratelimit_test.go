@@ -0,0 +1,58 @@
+package echobrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/ratelimitbrick"
+)
+
+func TestRateLimitMW(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(nil)
+	limiter := ratelimitbrick.NewTokenBucket(ratelimitbrick.NewMemoryStore(), 0.001, 1)
+	e.Use(RateLimitMW(limiter, nil, func(c echo.Context) bool {
+		return c.Path() == "/health"
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		wantCode   int
+	}{
+		{name: "RateLimitMW_WithFirstReq_Allows", path: "/", remoteAddr: "10.0.0.1:1000", wantCode: http.StatusOK},
+		{name: "RateLimitMW_WithExceededLimit_Rejects", path: "/", remoteAddr: "10.0.0.1:2000", wantCode: http.StatusTooManyRequests},
+		{name: "RateLimitMW_WithAnotherIP_Allows", path: "/", remoteAddr: "10.0.0.2:1000", wantCode: http.StatusOK},
+		{name: "RateLimitMW_WithSkipper_Skips", path: "/health", remoteAddr: "10.0.0.1:1000", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			rr := httptest.NewRecorder()
+			e.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.path == "/health" {
+				assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+				return
+			}
+			assert.Equal(t, "1", rr.Header().Get("RateLimit-Limit"))
+			assert.NotEmpty(t, rr.Header().Get("RateLimit-Reset"))
+			if tt.wantCode == http.StatusTooManyRequests {
+				assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+				assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
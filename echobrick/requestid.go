@@ -0,0 +1,14 @@
+package echobrick
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/demeero/bricks/httpbrick"
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+// RequestIDMW is an Echo variant of httpbrick.RequestIDMW.
+// Put it after SlogCtxMW, so the request logger gets the request ID.
+func RequestIDMW(options ...requestidbrick.Option) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(httpbrick.RequestIDMW(options...))
+}
@@ -0,0 +1,30 @@
+package echobrick
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/demeero/bricks/httpbrick"
+)
+
+// OTelTraceMW is an Echo variant of httpbrick.OTelTraceMW.
+// It uses c.Path() as the route for the span name and http.route attribute,
+// and passes the handler error to the echo error handler to record the actual response status.
+// Put it before SlogCtxMW, so the logger is correlated with the span.
+func OTelTraceMW(options ...httpbrick.OTelTraceMWOption) echo.MiddlewareFunc {
+	tracer := httpbrick.NewServerTracer(options...)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req, span, ok := tracer.Start(c.Request(), c.Path())
+			if !ok {
+				return next(c)
+			}
+			c.SetRequest(req)
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			tracer.End(span, c.Response().Status, err)
+			return err
+		}
+	}
+}
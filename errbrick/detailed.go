@@ -0,0 +1,151 @@
+package errbrick
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// FieldViolation describes a single invalid field of the request.
+type FieldViolation struct {
+	// Field is a path to the invalid field, e.g. "user.email".
+	Field string `json:"field"`
+	// Description describes why the field is invalid.
+	Description string `json:"description"`
+}
+
+// DetailedError is an error that wraps one of the errbrick errors (the kind) and carries machine-readable details.
+// Transport layers (grpcbrick, echobrick, httpbrick) map the kind to a status code and pass the details to the client.
+// Example:
+//
+//	errbrick.NewDetailed(errbrick.ErrInvalidData, "invalid user",
+//		errbrick.WithReason("USER_INVALID"),
+//		errbrick.WithFieldViolation("email", "must be a valid email address"))
+//
+// The caller can still check the kind with errors.Is(err, errbrick.ErrInvalidData).
+type DetailedError struct {
+	// Kind is one of the errbrick errors, e.g. errbrick.ErrInvalidData.
+	Kind error
+	// Metadata is an additional structured information about the error.
+	Metadata map[string]string
+	// Reason is a machine-readable error code, e.g. "USER_INVALID".
+	Reason string
+	// Domain is a logical grouping of the Reason, e.g. the service name.
+	Domain string
+	// Msg is a human-readable error message.
+	Msg string
+	// Violations is a list of invalid fields.
+	Violations []FieldViolation
+	// RetryAfter is a hint for the client when the request can be retried.
+	RetryAfter time.Duration
+}
+
+// DetailOption is a function that configures DetailedError.
+type DetailOption func(*DetailedError)
+
+// WithReason sets the machine-readable error code.
+func WithReason(reason string) DetailOption {
+	return func(e *DetailedError) {
+		e.Reason = reason
+	}
+}
+
+// WithDomain sets the logical grouping of the error reason.
+func WithDomain(domain string) DetailOption {
+	return func(e *DetailedError) {
+		e.Domain = domain
+	}
+}
+
+// WithFieldViolation adds an invalid field description.
+func WithFieldViolation(field, description string) DetailOption {
+	return func(e *DetailedError) {
+		e.Violations = append(e.Violations, FieldViolation{Field: field, Description: description})
+	}
+}
+
+// WithMetadata adds a key-value pair to the error metadata.
+func WithMetadata(key, value string) DetailOption {
+	return func(e *DetailedError) {
+		if e.Metadata == nil {
+			e.Metadata = make(map[string]string)
+		}
+		e.Metadata[key] = value
+	}
+}
+
+// WithRetryAfter sets the retry hint.
+func WithRetryAfter(d time.Duration) DetailOption {
+	return func(e *DetailedError) {
+		e.RetryAfter = d
+	}
+}
+
+// NewDetailed creates a new DetailedError of the given kind.
+func NewDetailed(kind error, msg string, options ...DetailOption) *DetailedError {
+	e := &DetailedError{Kind: kind, Msg: msg}
+	for _, opt := range options {
+		opt(e)
+	}
+	return e
+}
+
+func (e *DetailedError) Error() string {
+	switch {
+	case e.Kind == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Kind.Error()
+	default:
+		return fmt.Sprintf("%s: %s", e.Kind, e.Msg)
+	}
+}
+
+func (e *DetailedError) Unwrap() error {
+	return e.Kind
+}
+
+// AsDetailed finds the first DetailedError in err's tree.
+func AsDetailed(err error) (*DetailedError, bool) {
+	var detailed *DetailedError
+	if errors.As(err, &detailed) {
+		return detailed, true
+	}
+	return nil, false
+}
+
+// ErrorBody is a JSON representation of an error that is sent to HTTP clients.
+type ErrorBody struct {
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Message    string            `json:"message"`
+	Reason     string            `json:"reason,omitempty"`
+	Domain     string            `json:"domain,omitempty"`
+	Violations []FieldViolation  `json:"violations,omitempty"`
+	// RetryAfter is a retry hint in seconds.
+	RetryAfter int64 `json:"retry_after,omitempty"`
+}
+
+// NewErrorBody creates ErrorBody from the error.
+// If the error is a DetailedError (or wraps it), the details are added to the body.
+func NewErrorBody(err error) ErrorBody {
+	body := ErrorBody{Message: err.Error()}
+	detailed, ok := AsDetailed(err)
+	if !ok {
+		return body
+	}
+	body.Reason = detailed.Reason
+	body.Domain = detailed.Domain
+	body.Metadata = detailed.Metadata
+	body.Violations = detailed.Violations
+	body.RetryAfter = RetryAfterSeconds(detailed.RetryAfter)
+	return body
+}
+
+// RetryAfterSeconds rounds the retry hint up to whole seconds, as it's expected by the Retry-After HTTP header.
+func RetryAfterSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,55 @@
+package errbrick
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetailedError(t *testing.T) {
+	err := NewDetailed(ErrInvalidData, "invalid user",
+		WithReason("USER_INVALID"),
+		WithDomain("users"),
+		WithFieldViolation("email", "must be a valid email"),
+		WithFieldViolation("age", "must be positive"),
+		WithMetadata("user_id", "42"),
+		WithRetryAfter(1500*time.Millisecond))
+
+	assert.Equal(t, "invalid data: invalid user", err.Error())
+	assert.ErrorIs(t, err, ErrInvalidData)
+	assert.True(t, IsOneOf(err))
+
+	wrapped := fmt.Errorf("failed create user: %w", err)
+	detailed, ok := AsDetailed(wrapped)
+	require.True(t, ok)
+	assert.Same(t, err, detailed)
+
+	body := NewErrorBody(wrapped)
+	assert.Equal(t, ErrorBody{
+		Metadata: map[string]string{"user_id": "42"},
+		Message:  "failed create user: invalid data: invalid user",
+		Reason:   "USER_INVALID",
+		Domain:   "users",
+		Violations: []FieldViolation{
+			{Field: "email", Description: "must be a valid email"},
+			{Field: "age", Description: "must be positive"},
+		},
+		RetryAfter: 2,
+	}, body)
+}
+
+func TestDetailedError_Error(t *testing.T) {
+	assert.Equal(t, "not found", NewDetailed(ErrNotFound, "").Error())
+	assert.Equal(t, "something", NewDetailed(nil, "something").Error())
+	assert.NotErrorIs(t, NewDetailed(nil, "something"), ErrNotFound)
+}
+
+func TestNewErrorBody_PlainError(t *testing.T) {
+	_, ok := AsDetailed(errors.New("plain"))
+	assert.False(t, ok)
+	assert.Equal(t, ErrorBody{Message: "plain"}, NewErrorBody(errors.New("plain")))
+}
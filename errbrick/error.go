@@ -1,6 +1,9 @@
 package errbrick
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrInvalidData is a generic error that should be used when the input data is invalid.
@@ -37,22 +40,65 @@ var (
 	// So, the error message will be: "unauthenticated: invalid token", the error type will be errbrick.ErrUnauthenticated
 	// and the caller can check for it with errors.Is(err, errbrick.ErrUnauthenticated).
 	ErrUnauthenticated = errors.New("unauthenticated")
-)
 
-var errList = []error{
-	ErrInvalidData,
-	ErrNotFound,
-	ErrConflict,
-	ErrForbidden,
-	ErrUnauthenticated,
-}
+	// ErrRateLimited is a generic error that should be used when the subject exceeded the allowed rate of requests.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrRateLimited, "too many login attempts")
+	// So, the error message will be: "rate limited: too many login attempts", the error type will be errbrick.ErrRateLimited
+	// and the caller can check for it with errors.Is(err, errbrick.ErrRateLimited).
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnavailable is a generic error that should be used when the service or its dependency is temporarily unavailable.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrUnavailable, "payment provider is down")
+	// So, the error message will be: "unavailable: payment provider is down", the error type will be errbrick.ErrUnavailable
+	// and the caller can check for it with errors.Is(err, errbrick.ErrUnavailable).
+	ErrUnavailable = errors.New("unavailable")
+
+	// ErrDeadlineExceeded is a generic error that should be used when the operation didn't complete in time.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrDeadlineExceeded, "report generation timed out")
+	// So, the error message will be: "deadline exceeded: report generation timed out", the error type will be errbrick.ErrDeadlineExceeded
+	// and the caller can check for it with errors.Is(err, errbrick.ErrDeadlineExceeded).
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+	// ErrPreconditionFailed is a generic error that should be used when the system is not in a state required for the operation.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrPreconditionFailed, "order is already shipped")
+	// So, the error message will be: "precondition failed: order is already shipped", the error type will be errbrick.ErrPreconditionFailed
+	// and the caller can check for it with errors.Is(err, errbrick.ErrPreconditionFailed).
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrNotImplemented is a generic error that should be used when the operation is not implemented or not supported.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrNotImplemented, "export to xls")
+	// So, the error message will be: "not implemented: export to xls", the error type will be errbrick.ErrNotImplemented
+	// and the caller can check for it with errors.Is(err, errbrick.ErrNotImplemented).
+	ErrNotImplemented = errors.New("not implemented")
+
+	// ErrCanceled is a generic error that should be used when the operation was canceled, typically by the caller.
+	// It should be used with a more specific error message.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrCanceled, "import aborted by user")
+	// So, the error message will be: "canceled: import aborted by user", the error type will be errbrick.ErrCanceled
+	// and the caller can check for it with errors.Is(err, errbrick.ErrCanceled).
+	ErrCanceled = errors.New("canceled")
+
+	// ErrCircuitOpen is an error that should be used when the call is rejected by an open circuit breaker without reaching the dependency.
+	// It wraps ErrUnavailable, so the callers that handle ErrUnavailable handle it as well.
+	// Example: fmt.Errorf("%w: %s", errbrick.ErrCircuitOpen, "payments")
+	// So, the error message will be: "unavailable: circuit open: payments", the error type will be errbrick.ErrCircuitOpen
+	// and the caller can check for it with errors.Is(err, errbrick.ErrCircuitOpen).
+	ErrCircuitOpen = fmt.Errorf("%w: circuit open", ErrUnavailable)
+)
 
+// IsOneOf reports whether err matches any of errs.
+// If errs is empty, err is checked against all errors in the registry (see Register).
 func IsOneOf(err error, errs ...error) bool {
 	if err == nil {
 		return false
 	}
 	if len(errs) == 0 {
-		errs = append(errs, errList...)
+		errs = Registered()
 	}
 	for _, e := range errs {
 		if errors.Is(err, e) {
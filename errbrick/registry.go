@@ -0,0 +1,115 @@
+package errbrick
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// StatusClientClosedRequest is a non-standard HTTP status code used when the client canceled the request.
+const StatusClientClosedRequest = 499
+
+// Mapping describes how an error is represented by HTTP and gRPC transports.
+type Mapping struct {
+	Err        error
+	HTTPStatus int
+	GRPCCode   codes.Code
+}
+
+var registry = struct {
+	mappings []Mapping
+	mu       sync.RWMutex
+}{
+	mappings: []Mapping{
+		{Err: ErrInvalidData, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument},
+		{Err: ErrNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound},
+		{Err: ErrConflict, HTTPStatus: http.StatusConflict, GRPCCode: codes.AlreadyExists},
+		{Err: ErrForbidden, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied},
+		{Err: ErrUnauthenticated, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated},
+		{Err: ErrRateLimited, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted},
+		{Err: ErrUnavailable, HTTPStatus: http.StatusServiceUnavailable, GRPCCode: codes.Unavailable},
+		{Err: ErrDeadlineExceeded, HTTPStatus: http.StatusGatewayTimeout, GRPCCode: codes.DeadlineExceeded},
+		{Err: ErrPreconditionFailed, HTTPStatus: http.StatusPreconditionFailed, GRPCCode: codes.FailedPrecondition},
+		{Err: ErrNotImplemented, HTTPStatus: http.StatusNotImplemented, GRPCCode: codes.Unimplemented},
+		{Err: ErrCanceled, HTTPStatus: StatusClientClosedRequest, GRPCCode: codes.Canceled},
+		{Err: ErrCircuitOpen, HTTPStatus: http.StatusServiceUnavailable, GRPCCode: codes.Unavailable},
+	},
+}
+
+// Register registers a mapping of the error to HTTP status and gRPC code.
+// Use it to add custom errors, so all transports (echobrick, httpbrick, grpcbrick) handle them the same way.
+// Registering an already registered error replaces its mapping.
+// The errors of non-comparable types (e.g. a struct with a slice field) can't be compared by identity,
+// so registering such an error again adds a new mapping that takes precedence instead of replacing it.
+// Later registrations take precedence in Lookup,
+// so a custom error that wraps a built-in one (e.g. fmt.Errorf("%w: quota", ErrRateLimited)) can override its mapping.
+func Register(err error, httpStatus int, grpcCode codes.Code) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	m := Mapping{Err: err, HTTPStatus: httpStatus, GRPCCode: grpcCode}
+	// comparing the interfaces with the same non-comparable dynamic type panics, errors.Is guards it the same way
+	if err == nil || reflect.TypeOf(err).Comparable() {
+		for i := range registry.mappings {
+			if registry.mappings[i].Err == err { //nolint:errorlint // sentinels are compared by identity
+				registry.mappings[i] = m
+				return
+			}
+		}
+	}
+	registry.mappings = append(registry.mappings, m)
+}
+
+// Lookup returns the mapping of the registered error that err matches (see errors.Is).
+func Lookup(err error) (Mapping, bool) {
+	if err == nil {
+		return Mapping{}, false
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for i := len(registry.mappings) - 1; i >= 0; i-- {
+		if errors.Is(err, registry.mappings[i].Err) {
+			return registry.mappings[i], true
+		}
+	}
+	return Mapping{}, false
+}
+
+// LookupGRPCCode returns the mapping for the gRPC code.
+// If several errors are registered with the same code, the first registered one is returned.
+func LookupGRPCCode(code codes.Code) (Mapping, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for _, m := range registry.mappings {
+		if m.GRPCCode == code {
+			return m, true
+		}
+	}
+	return Mapping{}, false
+}
+
+// LookupHTTPStatus returns the mapping for the HTTP status.
+// If several errors are registered with the same status, the first registered one is returned.
+func LookupHTTPStatus(status int) (Mapping, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for _, m := range registry.mappings {
+		if m.HTTPStatus == status {
+			return m, true
+		}
+	}
+	return Mapping{}, false
+}
+
+// Registered returns all registered errors.
+func Registered() []error {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	errs := make([]error, 0, len(registry.mappings))
+	for _, m := range registry.mappings {
+		errs = append(errs, m.Err)
+	}
+	return errs
+}
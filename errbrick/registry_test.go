@@ -0,0 +1,116 @@
+package errbrick
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		err        error
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{err: ErrInvalidData, httpStatus: http.StatusBadRequest, grpcCode: codes.InvalidArgument},
+		{err: ErrNotFound, httpStatus: http.StatusNotFound, grpcCode: codes.NotFound},
+		{err: ErrConflict, httpStatus: http.StatusConflict, grpcCode: codes.AlreadyExists},
+		{err: ErrForbidden, httpStatus: http.StatusForbidden, grpcCode: codes.PermissionDenied},
+		{err: ErrUnauthenticated, httpStatus: http.StatusUnauthorized, grpcCode: codes.Unauthenticated},
+		{err: ErrRateLimited, httpStatus: http.StatusTooManyRequests, grpcCode: codes.ResourceExhausted},
+		{err: ErrUnavailable, httpStatus: http.StatusServiceUnavailable, grpcCode: codes.Unavailable},
+		{err: ErrDeadlineExceeded, httpStatus: http.StatusGatewayTimeout, grpcCode: codes.DeadlineExceeded},
+		{err: ErrPreconditionFailed, httpStatus: http.StatusPreconditionFailed, grpcCode: codes.FailedPrecondition},
+		{err: ErrNotImplemented, httpStatus: http.StatusNotImplemented, grpcCode: codes.Unimplemented},
+		{err: ErrCanceled, httpStatus: StatusClientClosedRequest, grpcCode: codes.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			m, ok := Lookup(fmt.Errorf("wrapped: %w", tt.err))
+			require.True(t, ok)
+			assert.Equal(t, tt.httpStatus, m.HTTPStatus)
+			assert.Equal(t, tt.grpcCode, m.GRPCCode)
+
+			m, ok = LookupGRPCCode(tt.grpcCode)
+			require.True(t, ok)
+			assert.Equal(t, tt.err, m.Err)
+
+			m, ok = LookupHTTPStatus(tt.httpStatus)
+			require.True(t, ok)
+			assert.Equal(t, tt.err, m.Err)
+
+			assert.True(t, IsOneOf(tt.err))
+		})
+	}
+
+	m, ok := Lookup(fmt.Errorf("payments: %w", ErrCircuitOpen))
+	require.True(t, ok)
+	assert.Equal(t, ErrCircuitOpen, m.Err)
+	assert.Equal(t, http.StatusServiceUnavailable, m.HTTPStatus)
+	assert.Equal(t, codes.Unavailable, m.GRPCCode)
+	assert.ErrorIs(t, ErrCircuitOpen, ErrUnavailable)
+
+	_, ok = Lookup(errors.New("unknown"))
+	assert.False(t, ok)
+	_, ok = Lookup(nil)
+	assert.False(t, ok)
+	_, ok = LookupGRPCCode(codes.Internal)
+	assert.False(t, ok)
+}
+
+func TestRegister(t *testing.T) {
+	errPaymentRequired := errors.New("payment required")
+	errQuotaExceeded := fmt.Errorf("%w: quota exceeded", ErrRateLimited)
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		registry.mappings = registry.mappings[:len(registry.mappings)-2]
+	})
+
+	Register(errPaymentRequired, http.StatusPaymentRequired, codes.FailedPrecondition)
+	Register(errQuotaExceeded, http.StatusForbidden, codes.ResourceExhausted)
+	Register(errQuotaExceeded, http.StatusTooManyRequests, codes.ResourceExhausted)
+
+	m, ok := Lookup(errPaymentRequired)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusPaymentRequired, m.HTTPStatus)
+	assert.True(t, IsOneOf(errPaymentRequired))
+
+	// built-in errors registered first win the reverse lookup
+	m, ok = LookupGRPCCode(codes.FailedPrecondition)
+	require.True(t, ok)
+	assert.Equal(t, ErrPreconditionFailed, m.Err)
+
+	// custom errors registered later take precedence over the wrapped built-in ones
+	m, ok = Lookup(fmt.Errorf("wrapped: %w", errQuotaExceeded))
+	require.True(t, ok)
+	assert.Equal(t, errQuotaExceeded, m.Err)
+	assert.Equal(t, http.StatusTooManyRequests, m.HTTPStatus)
+}
+
+type fieldsError struct {
+	fields []string
+}
+
+func (e fieldsError) Error() string { return "invalid fields" }
+
+func TestRegister_WithNonComparableError(t *testing.T) {
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		registry.mappings = registry.mappings[:len(registry.mappings)-2]
+	})
+
+	assert.NotPanics(t, func() {
+		Register(fieldsError{fields: []string{"name"}}, http.StatusBadRequest, codes.InvalidArgument)
+		Register(fieldsError{fields: []string{"age"}}, http.StatusUnprocessableEntity, codes.InvalidArgument)
+	})
+	m, ok := LookupHTTPStatus(http.StatusUnprocessableEntity)
+	require.True(t, ok)
+	assert.Equal(t, fieldsError{fields: []string{"age"}}, m.Err)
+}
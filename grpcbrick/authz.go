@@ -0,0 +1,37 @@
+package grpcbrick
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+// AuthorizeUnaryServerInterceptor is an interceptor that checks the token claims against the policies (see jwtbrick.Authorize).
+// Errors are converted to codes.Unauthenticated and codes.PermissionDenied.
+func AuthorizeUnaryServerInterceptor(skipper Skipper, policies ...jwtbrick.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if skipper != nil && skipper(ctx, req, info) {
+			return handler(ctx, req)
+		}
+		if err := jwtbrick.Authorize(ctx, policies...); err != nil {
+			return nil, statusErr(ctx, err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthorizeStreamServerInterceptor is a stream equivalent of AuthorizeUnaryServerInterceptor.
+func AuthorizeStreamServerInterceptor(skipper StreamSkipper, policies ...jwtbrick.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if skipper != nil && skipper(ctx, info) {
+			return handler(srv, ss)
+		}
+		if err := jwtbrick.Authorize(ctx, policies...); err != nil {
+			return statusErr(ctx, err)
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,47 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestAuthorizeUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{
+			name:     "authorized",
+			ctx:      jwtbrick.ClaimsToCtx(context.Background(), jwt.MapClaims{"roles": []interface{}{"admin"}}),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "forbidden",
+			ctx:      jwtbrick.ClaimsToCtx(context.Background(), jwt.MapClaims{"roles": []interface{}{"viewer"}}),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unauthenticated",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intercept := AuthorizeUnaryServerInterceptor(nil, jwtbrick.RequireRoles("admin"))
+			_, err := intercept(tt.ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package grpcbrick
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/otelbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// BaggageUnaryServerInterceptor is an interceptor that promotes the W3C baggage members with keys from the allow-list
+// to the context attributes and the request logger (see otelbrick.BaggageToCtx).
+// The context attributes are used in metrics, so allow only the keys with bounded values
+// or the ones overridden by claimsMapping: the client controls the rest of the baggage.
+// The baggage is extracted from the metadata unless it's already in the context.
+// If claimsMapping isn't empty, the token claims are set to the baggage first (see jwtbrick.ClaimsToBaggage),
+// replacing the members with the mapped keys sent by the client, so put it after TokenClaimsUnaryServerInterceptor.
+func BaggageUnaryServerInterceptor(allowList []string, claimsMapping map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(baggageToCtx(ctx, allowList, claimsMapping), req)
+	}
+}
+
+// BaggageStreamServerInterceptor is a stream equivalent of BaggageUnaryServerInterceptor.
+func BaggageStreamServerInterceptor(allowList []string, claimsMapping map[string]string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, wrapServerStream(baggageToCtx(ss.Context(), allowList, claimsMapping), ss))
+	}
+}
+
+// BaggageUnaryClientInterceptor is an interceptor that sends the W3C baggage from the context in the metadata
+// unless the outgoing metadata already has it.
+func BaggageUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingBaggage(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// BaggageStreamClientInterceptor is a stream equivalent of BaggageUnaryClientInterceptor.
+func BaggageStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingBaggage(ctx), desc, cc, method, opts...)
+	}
+}
+
+func baggageToCtx(ctx context.Context, allowList []string, claimsMapping map[string]string) context.Context {
+	if baggage.FromContext(ctx).Len() == 0 {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagation.Baggage{}.Extract(ctx, metadataCarrier(md))
+	}
+	if len(claimsMapping) > 0 {
+		var err error
+		if ctx, err = jwtbrick.ClaimsToBaggage(ctx, TokenClaimsFromCtx(ctx), claimsMapping); err != nil {
+			slogbrick.FromCtx(ctx).Warn("failed set baggage from token claims", slog.Any("err", err))
+		}
+	}
+	return otelbrick.BaggageToCtx(ctx, allowList...)
+}
+
+func outgoingBaggage(ctx context.Context) context.Context {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return ctx
+	}
+	// the receiving side reads the first value only, so the baggage set by the caller or a propagator wins
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("baggage")) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "baggage", bag.String())
+}
+
+// metadataCarrier adapts metadata.MD to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
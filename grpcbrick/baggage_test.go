@@ -0,0 +1,58 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+func TestBaggageInterceptors(t *testing.T) {
+	ctx, err := otelbrick.SetBaggage(context.Background(), map[string]string{"tenant.id": "acme", "session.id": "s1"})
+	require.NoError(t, err)
+
+	var outgoing metadata.MD
+	err = BaggageUnaryClientInterceptor()(ctx, "/svc/M", nil, nil, nil,
+		func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	require.NoError(t, err)
+
+	var (
+		gotAttrs []attribute.KeyValue
+		gotBag   baggage.Baggage
+	)
+	_, err = BaggageUnaryServerInterceptor([]string{"tenant.id"}, nil)(
+		metadata.NewIncomingContext(context.Background(), outgoing), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			gotAttrs = otelbrick.AttrsFromCtx(ctx)
+			gotBag = baggage.FromContext(ctx)
+			return nil, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tenant.id", "acme")}, gotAttrs)
+	assert.Equal(t, 2, gotBag.Len())
+}
+
+func TestBaggageUnaryClientInterceptor_WithOutgoingBaggage_KeepsIt(t *testing.T) {
+	ctx, err := otelbrick.SetBaggage(context.Background(), map[string]string{"tenant.id": "acme"})
+	require.NoError(t, err)
+	ctx = metadata.AppendToOutgoingContext(ctx, "baggage", "tenant.id=caller")
+
+	var outgoing metadata.MD
+	err = BaggageUnaryClientInterceptor()(ctx, "/svc/M", nil, nil, nil,
+		func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant.id=caller"}, outgoing.Get("baggage"))
+}
@@ -0,0 +1,40 @@
+package grpcbrick
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/breakerbrick"
+)
+
+// BreakerUnaryClientInterceptor is an interceptor that protects the calls with the circuit breaker.
+// Unavailable, DeadlineExceeded, ResourceExhausted, Internal and Unknown codes are recorded as failures,
+// other codes mean the server is healthy and handles the requests.
+// If the circuit is open, the call isn't sent and the error wrapping errbrick.ErrCircuitOpen is returned.
+func BreakerUnaryClientInterceptor(breaker *breakerbrick.Breaker, skipper ClientSkipper) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if skipper != nil && skipper(ctx, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		done, err := breaker.Allow(ctx)
+		if err != nil {
+			return err
+		}
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		done(breakerFailure(err))
+		return err
+	}
+}
+
+// breakerFailure returns the error if it indicates the server failure, nil otherwise.
+func breakerFailure(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/breakerbrick"
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestBreakerUnaryClientInterceptor(t *testing.T) {
+	breaker, err := breakerbrick.New("users", breakerbrick.WithMinCalls(2))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = breaker.Close() })
+	intercept := BreakerUnaryClientInterceptor(breaker, nil)
+	invoke := func(code codes.Code) error {
+		return intercept(context.Background(), "/svc.Users/Get", nil, nil, nil,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				return status.Error(code, code.String())
+			})
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, codes.NotFound, status.Code(invoke(codes.NotFound)))
+	}
+	assert.Equal(t, breakerbrick.StateClosed, breaker.State())
+
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(codes.Unavailable)))
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(codes.Unavailable)))
+	assert.Equal(t, breakerbrick.StateOpen, breaker.State())
+	assert.ErrorIs(t, invoke(codes.OK), errbrick.ErrCircuitOpen)
+}
@@ -0,0 +1,195 @@
+package grpcbrick
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// ClientSkipper defines a function to skip client interceptors.
+// Returning true skips processing the interceptor.
+type ClientSkipper func(ctx context.Context, method string) bool
+
+// statusError is a gRPC status error that unwraps to the corresponding errbrick error.
+// It keeps the original status, so status.FromError and status.Code keep working.
+type statusError struct {
+	cause  error
+	kind   error
+	status *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.kind
+}
+
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// errFromStatus wraps gRPC status errors, so callers can check them with errors.Is(err, errbrick.ErrNotFound) etc.
+// google.rpc error details of the status are decoded into errbrick.DetailedError.
+// Errors that are not gRPC status errors or don't have a registered errbrick error are returned as is.
+func errFromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	mapping, ok := errbrick.LookupGRPCCode(st.Code())
+	if !ok {
+		return err
+	}
+	kind := mapping.Err
+	if detailed := detailedFromStatus(kind, st); detailed != nil {
+		kind = detailed
+	}
+	return &statusError{cause: err, kind: kind, status: st}
+}
+
+// ErrUnaryClientInterceptor wraps received gRPC status errors,
+// so errors.Is(err, errbrick.ErrNotFound) works on the caller side.
+// The original status is preserved and can be obtained via status.FromError.
+func ErrUnaryClientInterceptor(skipper ClientSkipper) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if skipper != nil && skipper(ctx, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return errFromStatus(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// ErrStreamClientInterceptor is a stream equivalent of ErrUnaryClientInterceptor.
+func ErrStreamClientInterceptor(skipper ClientSkipper) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if skipper != nil && skipper(ctx, method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, errFromStatus(err)
+		}
+		return &errClientStream{ClientStream: cs}, nil
+	}
+}
+
+type errClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	return md, errFromStatus(err)
+}
+
+func (s *errClientStream) CloseSend() error {
+	return errFromStatus(s.ClientStream.CloseSend())
+}
+
+func (s *errClientStream) SendMsg(m interface{}) error {
+	return errFromStatus(s.ClientStream.SendMsg(m))
+}
+
+func (s *errClientStream) RecvMsg(m interface{}) error {
+	return errFromStatus(s.ClientStream.RecvMsg(m))
+}
+
+// SlogUnaryClientInterceptor is a client equivalent of SlogUnaryServerInterceptor.
+// It logs outgoing requests and incoming responses with the request duration and the response code.
+func SlogUnaryClientInterceptor(lvl slog.Level, skipper ClientSkipper) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if skipper != nil && skipper(ctx, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		reqLogger := slogbrick.FromCtx(ctx).With(slog.String("grpc_method", method))
+		reqLogger.Log(ctx, lvl, "outgoing grpc req")
+
+		startTime := time.Now().UTC()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			reqLogger = reqLogger.With(slog.Any("err", err))
+		}
+
+		reqLogger.Log(ctx, lvl, "incoming grpc resp",
+			slog.Int64("grpc_req_duration_ms", time.Since(startTime).Milliseconds()),
+			slog.String("grpc_resp_code", status.Code(err).String()))
+		return err
+	}
+}
+
+// SlogStreamClientInterceptor is a client equivalent of SlogStreamServerInterceptor.
+// It logs stream opening and closing with the number of sent/received messages and the stream duration.
+// The stream is considered closed when RecvMsg returns an error (including io.EOF).
+func SlogStreamClientInterceptor(lvl slog.Level, skipper ClientSkipper) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if skipper != nil && skipper(ctx, method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		reqLogger := slogbrick.FromCtx(ctx).With(slog.String("grpc_method", method))
+		reqLogger.Log(ctx, lvl, "outgoing grpc stream")
+
+		startTime := time.Now().UTC()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			reqLogger.Log(ctx, lvl, "grpc stream closed",
+				slog.Any("err", err),
+				slog.Int64("grpc_req_duration_ms", time.Since(startTime).Milliseconds()),
+				slog.String("grpc_resp_code", status.Code(err).String()))
+			return nil, err
+		}
+		return &slogClientStream{ClientStream: cs, ctx: ctx, lg: reqLogger, lvl: lvl, startTime: startTime}, nil
+	}
+}
+
+type slogClientStream struct {
+	grpc.ClientStream
+	startTime time.Time
+	ctx       context.Context
+	lg        *slog.Logger
+	once      sync.Once
+	recv      atomic.Int64
+	sent      atomic.Int64
+	lvl       slog.Level
+}
+
+func (s *slogClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+func (s *slogClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		s.recv.Add(1)
+		return nil
+	}
+	s.once.Do(func() {
+		lg, code := s.lg, codes.OK
+		if !errors.Is(err, io.EOF) {
+			lg, code = lg.With(slog.Any("err", err)), status.Code(err)
+		}
+		lg.Log(s.ctx, s.lvl, "grpc stream closed",
+			slog.Int64("grpc_req_duration_ms", time.Since(s.startTime).Milliseconds()),
+			slog.String("grpc_resp_code", code.String()),
+			slog.Int64("grpc_msgs_received", s.recv.Load()),
+			slog.Int64("grpc_msgs_sent", s.sent.Load()))
+	})
+	return err
+}
@@ -0,0 +1,146 @@
+package grpcbrick
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestErrUnaryClientInterceptor(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		wantKind error
+		wantCode codes.Code
+	}{
+		{
+			name:     "InvalidArgument",
+			err:      status.Error(codes.InvalidArgument, "bad"),
+			wantKind: errbrick.ErrInvalidData,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "NotFound",
+			err:      status.Error(codes.NotFound, "no user"),
+			wantKind: errbrick.ErrNotFound,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "PermissionDenied",
+			err:      status.Error(codes.PermissionDenied, "denied"),
+			wantKind: errbrick.ErrForbidden,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "AlreadyExists",
+			err:      status.Error(codes.AlreadyExists, "exists"),
+			wantKind: errbrick.ErrConflict,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "Unauthenticated",
+			err:      status.Error(codes.Unauthenticated, "no token"),
+			wantKind: errbrick.ErrUnauthenticated,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "Internal",
+			err:      status.Error(codes.Internal, "internal error"),
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intercept := ErrUnaryClientInterceptor(nil)
+			err := intercept(context.Background(), "/test.Service/Method", nil, nil, nil,
+				func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					return tt.err
+				})
+			require.Error(t, err)
+			assert.Equal(t, tt.err.Error(), err.Error())
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantKind != nil {
+				assert.ErrorIs(t, err, tt.wantKind)
+				assert.True(t, errbrick.IsOneOf(err))
+			} else {
+				assert.False(t, errbrick.IsOneOf(err))
+			}
+		})
+	}
+}
+
+func TestErrUnaryClientInterceptor_NoError(t *testing.T) {
+	intercept := ErrUnaryClientInterceptor(nil)
+	err := intercept(context.Background(), "/test.Service/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+	recvErrs []error
+}
+
+func (s *testClientStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func (s *testClientStream) RecvMsg(interface{}) error {
+	err := s.recvErrs[0]
+	s.recvErrs = s.recvErrs[1:]
+	return err
+}
+
+func TestErrStreamClientInterceptor(t *testing.T) {
+	intercept := ErrStreamClientInterceptor(nil)
+	cs, err := intercept(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &testClientStream{recvErrs: []error{nil, status.Error(codes.NotFound, "gone"), io.EOF}}, nil
+		})
+	require.NoError(t, err)
+
+	require.NoError(t, cs.RecvMsg(nil))
+	err = cs.RecvMsg(nil)
+	assert.ErrorIs(t, err, errbrick.ErrNotFound)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+}
+
+func TestSlogClientInterceptors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+
+	unary := SlogUnaryClientInterceptor(slog.LevelInfo, nil)
+	err := unary(context.Background(), "/test.Service/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "gone")
+		})
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), `msg="outgoing grpc req" grpc_method=/test.Service/Method`)
+	assert.Contains(t, buf.String(), "grpc_resp_code=NotFound")
+
+	buf.Reset()
+	stream := SlogStreamClientInterceptor(slog.LevelInfo, nil)
+	cs, err := stream(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &testClientStream{recvErrs: []error{nil, io.EOF}}, nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, cs.SendMsg(nil))
+	require.NoError(t, cs.RecvMsg(nil))
+	require.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+	assert.Contains(t, buf.String(), `msg="outgoing grpc stream" grpc_method=/test.Service/Stream`)
+	assert.Contains(t, buf.String(), "grpc_resp_code=OK grpc_msgs_received=1 grpc_msgs_sent=1")
+}
@@ -0,0 +1,88 @@
+package grpcbrick
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// detailedStatusErr creates a status error with the given code.
+// If err is errbrick.DetailedError, its details are encoded as google.rpc error details:
+// field violations as BadRequest, reason and metadata as ErrorInfo and retry hint as RetryInfo.
+func detailedStatusErr(ctx context.Context, code codes.Code, err error) error {
+	st := status.New(code, err.Error())
+	detailed, ok := errbrick.AsDetailed(err)
+	if !ok {
+		return st.Err()
+	}
+
+	var details []protoiface.MessageV1
+	if len(detailed.Violations) > 0 {
+		badReq := &errdetails.BadRequest{}
+		for _, v := range detailed.Violations {
+			badReq.FieldViolations = append(badReq.FieldViolations,
+				&errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+		}
+		details = append(details, badReq)
+	}
+	if detailed.Reason != "" || len(detailed.Metadata) > 0 {
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   detailed.Reason,
+			Domain:   detailed.Domain,
+			Metadata: detailed.Metadata,
+		})
+	}
+	if detailed.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(detailed.RetryAfter)})
+	}
+	if len(details) == 0 {
+		return st.Err()
+	}
+
+	stWithDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		slogbrick.FromCtx(ctx).Error("failed add details to grpc status", slog.Any("err", detailsErr))
+		return st.Err()
+	}
+	return stWithDetails.Err()
+}
+
+// detailedFromStatus decodes google.rpc error details of the status into errbrick.DetailedError of the given kind.
+// It returns nil if the status doesn't have any known details.
+func detailedFromStatus(kind error, st *status.Status) *errbrick.DetailedError {
+	var (
+		detailed = &errbrick.DetailedError{Kind: kind, Msg: strings.TrimPrefix(st.Message(), kind.Error()+": ")}
+		found    bool
+	)
+	for _, d := range st.Details() {
+		switch detail := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range detail.GetFieldViolations() {
+				detailed.Violations = append(detailed.Violations,
+					errbrick.FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+			found = true
+		case *errdetails.ErrorInfo:
+			detailed.Reason = detail.GetReason()
+			detailed.Domain = detail.GetDomain()
+			detailed.Metadata = detail.GetMetadata()
+			found = true
+		case *errdetails.RetryInfo:
+			detailed.RetryAfter = detail.GetRetryDelay().AsDuration()
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return detailed
+}
@@ -0,0 +1,84 @@
+package grpcbrick
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+// HealthServer is a grpc.health.v1 implementation backed by healthbrick.Registry.
+// The empty service name reports the readiness of the whole application, other names report the named checks.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	reg           *healthbrick.Registry
+	watchInterval time.Duration
+}
+
+// NewHealthServer creates a new HealthServer.
+// The watchInterval is the interval to re-run the checks for the Watch streams (5s if not positive).
+func NewHealthServer(reg *healthbrick.Registry, watchInterval time.Duration) *HealthServer {
+	if watchInterval <= 0 {
+		watchInterval = 5 * time.Second
+	}
+	return &HealthServer{reg: reg, watchInterval: watchInterval}
+}
+
+// Check implements healthpb.HealthServer.
+func (s *HealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, err := s.status(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch implements healthpb.HealthServer. It sends the status on start and each time it changes.
+func (s *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		st, err := s.status(ctx, req.GetService())
+		if status.Code(err) == codes.NotFound {
+			st = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		} else if err != nil {
+			return err
+		}
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) status(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	if service == "" {
+		if s.reg.Ready(ctx).Status == healthbrick.StatusDown {
+			return healthpb.HealthCheckResponse_NOT_SERVING, nil
+		}
+		return healthpb.HealthCheckResponse_SERVING, nil
+	}
+	res, err := s.reg.CheckOne(ctx, service)
+	if errors.Is(err, healthbrick.ErrCheckNotFound) {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, status.Error(codes.NotFound, "unknown service")
+	}
+	if res.Status == healthbrick.StatusDown {
+		return healthpb.HealthCheckResponse_NOT_SERVING, nil
+	}
+	return healthpb.HealthCheckResponse_SERVING, nil
+}
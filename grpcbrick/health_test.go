@@ -0,0 +1,45 @@
+package grpcbrick
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+func TestHealthServer_Check(t *testing.T) {
+	reg := healthbrick.NewRegistry()
+	reg.Register("db", func(context.Context) error { return nil })
+	reg.Register("cache", func(context.Context) error { return errors.New("connection refused") }, healthbrick.WithNonCritical())
+	srv := NewHealthServer(reg, 0)
+
+	tests := []struct {
+		name       string
+		service    string
+		wantStatus healthpb.HealthCheckResponse_ServingStatus
+		wantCode   codes.Code
+	}{
+		{name: "overall", service: "", wantStatus: healthpb.HealthCheckResponse_SERVING},
+		{name: "up", service: "db", wantStatus: healthpb.HealthCheckResponse_SERVING},
+		{name: "down", service: "cache", wantStatus: healthpb.HealthCheckResponse_NOT_SERVING},
+		{name: "unknown", service: "queue", wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.GetStatus())
+		})
+	}
+}
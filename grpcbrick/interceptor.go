@@ -2,7 +2,6 @@ package grpcbrick
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	"time"
 
@@ -53,30 +52,26 @@ func ErrUnaryServerInterceptor(skipper Skipper) grpc.UnaryServerInterceptor {
 			return handler(ctx, req)
 		}
 		resp, err := handler(ctx, req)
-		if err == nil {
-			return resp, nil
-		}
+		return resp, statusErr(ctx, err)
+	}
+}
 
-		// if it's a valid GRPC error - skip the handling
-		_, ok := status.FromError(err)
-		if ok {
-			return resp, err
-		}
+// statusErr converts errbrick errors to gRPC status errors via the errbrick registry (see errbrick.Register).
+// Unknown errors are logged and converted to codes.Internal.
+func statusErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
 
-		switch {
-		case errors.Is(err, errbrick.ErrInvalidData):
-			return resp, status.Error(codes.InvalidArgument, err.Error())
-		case errors.Is(err, errbrick.ErrNotFound):
-			return resp, status.Error(codes.NotFound, err.Error())
-		case errors.Is(err, errbrick.ErrForbidden):
-			return nil, status.Error(codes.PermissionDenied, err.Error())
-		case errors.Is(err, errbrick.ErrConflict):
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		case errors.Is(err, errbrick.ErrUnauthenticated):
-			return nil, status.Error(codes.Unauthenticated, err.Error())
-		default:
-			slogbrick.FromCtx(ctx).Error("internal err", slog.Any("err", err))
-			return resp, status.Error(codes.Internal, "internal error")
-		}
+	// if it's a valid GRPC error - skip the handling
+	_, ok := status.FromError(err)
+	if ok {
+		return err
+	}
+
+	if mapping, ok := errbrick.Lookup(err); ok {
+		return detailedStatusErr(ctx, mapping.GRPCCode, err)
 	}
+	slogbrick.FromCtx(ctx).Error("internal err", slog.Any("err", err))
+	return status.Error(codes.Internal, "internal error")
 }
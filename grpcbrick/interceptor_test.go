@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -56,6 +59,21 @@ func TestErrUnaryServerInterceptor(t *testing.T) {
 			err:     errbrick.ErrUnauthenticated,
 			wantErr: status.Error(codes.Unauthenticated, errbrick.ErrUnauthenticated.Error()),
 		},
+		{
+			name:    "ErrRateLimited",
+			err:     errbrick.ErrRateLimited,
+			wantErr: status.Error(codes.ResourceExhausted, errbrick.ErrRateLimited.Error()),
+		},
+		{
+			name:    "ErrDeadlineExceeded",
+			err:     errbrick.ErrDeadlineExceeded,
+			wantErr: status.Error(codes.DeadlineExceeded, errbrick.ErrDeadlineExceeded.Error()),
+		},
+		{
+			name:    "ErrNotImplemented",
+			err:     errbrick.ErrNotImplemented,
+			wantErr: status.Error(codes.Unimplemented, errbrick.ErrNotImplemented.Error()),
+		},
 		{
 			name: "no-error",
 		},
@@ -70,3 +88,46 @@ func TestErrUnaryServerInterceptor(t *testing.T) {
 		})
 	}
 }
+
+func TestErrUnaryServerInterceptor_Details(t *testing.T) {
+	detailedErr := errbrick.NewDetailed(errbrick.ErrInvalidData, "invalid user",
+		errbrick.WithReason("USER_INVALID"),
+		errbrick.WithDomain("users"),
+		errbrick.WithMetadata("user_id", "42"),
+		errbrick.WithFieldViolation("email", "required"),
+		errbrick.WithRetryAfter(time.Second))
+
+	intercept := ErrUnaryServerInterceptor(nil)
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, detailedErr
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "invalid data: invalid user", st.Message())
+	require.Len(t, st.Details(), 3)
+
+	badReq, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	assert.Equal(t, "email", badReq.GetFieldViolations()[0].GetField())
+	errInfo, ok := st.Details()[1].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "USER_INVALID", errInfo.GetReason())
+	assert.Equal(t, "users", errInfo.GetDomain())
+	assert.Equal(t, map[string]string{"user_id": "42"}, errInfo.GetMetadata())
+	retryInfo, ok := st.Details()[2].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, time.Second, retryInfo.GetRetryDelay().AsDuration())
+
+	// the client side decodes the details back
+	clientIntercept := ErrUnaryClientInterceptor(nil)
+	clientErr := clientIntercept(context.Background(), "/test.Service/Method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return err
+		})
+	assert.ErrorIs(t, clientErr, errbrick.ErrInvalidData)
+	assert.Equal(t, codes.InvalidArgument, status.Code(clientErr))
+	decoded, ok := errbrick.AsDetailed(clientErr)
+	require.True(t, ok)
+	assert.Equal(t, detailedErr, decoded)
+}
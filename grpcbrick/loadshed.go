@@ -0,0 +1,43 @@
+package grpcbrick
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/demeero/bricks/shedbrick"
+)
+
+// LoadShedUnaryServerInterceptor is an interceptor that limits the number of concurrent calls.
+// The calls over the limit wait briefly for a free slot (see shedbrick.WithQueueTimeout)
+// and are rejected with codes.Unavailable with the retry hint (errdetails.RetryInfo).
+func LoadShedUnaryServerInterceptor(limiter *shedbrick.Limiter, skipper Skipper) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if skipper != nil && skipper(ctx, req, info) {
+			return handler(ctx, req)
+		}
+		permit, err := limiter.Acquire(ctx)
+		if err != nil {
+			return nil, statusErr(ctx, err)
+		}
+		defer permit.Release()
+		return handler(ctx, req)
+	}
+}
+
+// LoadShedStreamServerInterceptor is a stream equivalent of LoadShedUnaryServerInterceptor.
+// A stream holds the slot until it's finished, but its duration doesn't adapt the limit.
+func LoadShedStreamServerInterceptor(limiter *shedbrick.Limiter, skipper StreamSkipper) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if skipper != nil && skipper(ctx, info) {
+			return handler(srv, ss)
+		}
+		permit, err := limiter.Acquire(ctx)
+		if err != nil {
+			return statusErr(ctx, err)
+		}
+		defer permit.Ignore()
+		return handler(srv, ss)
+	}
+}
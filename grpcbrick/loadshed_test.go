@@ -0,0 +1,42 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/shedbrick"
+)
+
+func TestLoadShedUnaryServerInterceptor(t *testing.T) {
+	limiter, err := shedbrick.New("test", shedbrick.WithLimit(1), shedbrick.WithQueueTimeout(0))
+	require.NoError(t, err)
+	defer limiter.Close()
+	intercept := LoadShedUnaryServerInterceptor(limiter, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Users/Get"}
+
+	_, err = intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		// the slot is taken by this call
+		_, err := intercept(ctx, req, info, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+		return nil, err
+	})
+	st := status.Convert(err)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	var retryInfo *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			retryInfo = ri
+		}
+	}
+	require.NotNil(t, retryInfo)
+	assert.Positive(t, retryInfo.GetRetryDelay().AsDuration())
+
+	_, err = intercept(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	assert.NoError(t, err)
+}
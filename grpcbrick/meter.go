@@ -0,0 +1,254 @@
+package grpcbrick
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+type grpcOTELMetrics struct {
+	durationHist      metric.Int64Histogram
+	reqSizeHist       metric.Int64Histogram
+	respSizeHist      metric.Int64Histogram
+	reqsPerRPCHist    metric.Int64Histogram
+	respsPerRPCHist   metric.Int64Histogram
+	activeReqsCounter metric.Int64UpDownCounter
+	opts              otelMeterMetricsOpts
+}
+
+func newGRPCOTelMetrics(opts otelMeterMetricsOpts) (*grpcOTELMetrics, error) {
+	grpcMeter := otel.GetMeterProvider().Meter("bricks/grpcbrick/OTELMeter")
+	result := &grpcOTELMetrics{opts: opts}
+	var err error
+	if opts.ActiveReqsCounter {
+		result.activeReqsCounter, err = grpcMeter.Int64UpDownCounter(opts.Names.ActiveReqsCounter,
+			metric.WithDescription("Number of in-flight RPCs."))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.ActiveReqsCounter, err)
+		}
+	}
+	if opts.Duration {
+		result.durationHist, err = grpcMeter.Int64Histogram(opts.Names.DurationHist,
+			metric.WithDescription("Duration of inbound RPCs."), metric.WithUnit("ms"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.DurationHist, err)
+		}
+	}
+	if opts.ReqSize {
+		result.reqSizeHist, err = grpcMeter.Int64Histogram(opts.Names.ReqSizeHist,
+			metric.WithDescription("Size of RPC request messages (uncompressed)."), metric.WithUnit("By"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.ReqSizeHist, err)
+		}
+	}
+	if opts.RespSize {
+		result.respSizeHist, err = grpcMeter.Int64Histogram(opts.Names.RespSizeHist,
+			metric.WithDescription("Size of RPC response messages (uncompressed)."), metric.WithUnit("By"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.RespSizeHist, err)
+		}
+	}
+	if opts.ReqsPerRPC {
+		result.reqsPerRPCHist, err = grpcMeter.Int64Histogram(opts.Names.ReqsPerRPCHist,
+			metric.WithDescription("Number of messages received per RPC."), metric.WithUnit("{count}"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.ReqsPerRPCHist, err)
+		}
+	}
+	if opts.RespsPerRPC {
+		result.respsPerRPCHist, err = grpcMeter.Int64Histogram(opts.Names.RespsPerRPCHist,
+			metric.WithDescription("Number of messages sent per RPC."), metric.WithUnit("{count}"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.RespsPerRPCHist, err)
+		}
+	}
+	return result, nil
+}
+
+func (m *grpcOTELMetrics) start(ctx context.Context) func() {
+	if !m.opts.ActiveReqsCounter {
+		return func() {}
+	}
+	m.activeReqsCounter.Add(ctx, 1)
+	return func() { m.activeReqsCounter.Add(ctx, -1) }
+}
+
+func (m *grpcOTELMetrics) recordReqSize(ctx context.Context, msg interface{}, attrs []attribute.KeyValue) {
+	if m.opts.ReqSize {
+		m.reqSizeHist.Record(ctx, msgSize(msg), metric.WithAttributes(attrs...))
+	}
+}
+
+func (m *grpcOTELMetrics) recordRespSize(ctx context.Context, msg interface{}, attrs []attribute.KeyValue) {
+	if m.opts.RespSize {
+		m.respSizeHist.Record(ctx, msgSize(msg), metric.WithAttributes(attrs...))
+	}
+}
+
+func (m *grpcOTELMetrics) recordRPC(ctx context.Context, duration time.Duration, recv, sent int64, attrs []attribute.KeyValue) {
+	if m.opts.Duration {
+		m.durationHist.Record(ctx, duration.Milliseconds(), metric.WithAttributes(attrs...))
+	}
+	if m.opts.ReqsPerRPC {
+		m.reqsPerRPCHist.Record(ctx, recv, metric.WithAttributes(attrs...))
+	}
+	if m.opts.RespsPerRPC {
+		m.respsPerRPCHist.Record(ctx, sent, metric.WithAttributes(attrs...))
+	}
+}
+
+func newOTelMeterOpts(options []OTelMeterOption) otelMeterOpts {
+	opts := otelMeterOpts{
+		Attrs: otelMeterAttrsOpts{
+			AttrsFromCtx: true,
+			AttrsToCtx:   true,
+		},
+		Metrics: otelMeterMetricsOpts{
+			Names: OTelMetricNames{
+				DurationHist:      "rpc.server.duration",
+				ReqSizeHist:       "rpc.server.request.size",
+				RespSizeHist:      "rpc.server.response.size",
+				ReqsPerRPCHist:    "rpc.server.requests_per_rpc",
+				RespsPerRPCHist:   "rpc.server.responses_per_rpc",
+				ActiveReqsCounter: "rpc.server.active_requests",
+			},
+			Duration:          true,
+			ReqSize:           true,
+			RespSize:          true,
+			ReqsPerRPC:        true,
+			RespsPerRPC:       true,
+			ActiveReqsCounter: true,
+		},
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+// OTelMeterUnaryServerInterceptor is an interceptor that records metrics for each unary RPC.
+// It is a gRPC equivalent of httpbrick.OTelMeterMW.
+func OTelMeterUnaryServerInterceptor(options ...OTelMeterOption) (grpc.UnaryServerInterceptor, error) {
+	opts := newOTelMeterOpts(options)
+	grpcMeter, err := newGRPCOTelMetrics(opts.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed create grpc metrics: %w", err)
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if opts.Skipper != nil && opts.Skipper(ctx, req, info) {
+			return handler(ctx, req)
+		}
+		defer grpcMeter.start(ctx)()
+
+		ctx, attrs := meterAttrs(ctx, info.FullMethod, opts.Attrs)
+		grpcMeter.recordReqSize(ctx, req, attrs)
+
+		startTime := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(startTime)
+
+		var sent int64
+		if err == nil {
+			sent = 1
+			grpcMeter.recordRespSize(ctx, resp, attrs)
+		}
+		attrs = append(attrs, semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+		grpcMeter.recordRPC(ctx, duration, 1, sent, attrs)
+		return resp, err
+	}, nil
+}
+
+// OTelMeterStreamServerInterceptor is an interceptor that records metrics for each stream RPC.
+// Message sizes are recorded for each message passing through the stream.
+func OTelMeterStreamServerInterceptor(options ...OTelMeterOption) (grpc.StreamServerInterceptor, error) {
+	opts := newOTelMeterOpts(options)
+	grpcMeter, err := newGRPCOTelMetrics(opts.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed create grpc metrics: %w", err)
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if opts.StreamSkipper != nil && opts.StreamSkipper(ctx, info) {
+			return handler(srv, ss)
+		}
+		defer grpcMeter.start(ctx)()
+
+		ctx, attrs := meterAttrs(ctx, info.FullMethod, opts.Attrs)
+		stream := &meterServerStream{
+			serverStream: wrapServerStream(ctx, ss),
+			meter:        grpcMeter,
+			attrs:        attrs,
+		}
+
+		startTime := time.Now()
+		err := handler(srv, stream)
+		duration := time.Since(startTime)
+
+		attrs = append(attrs, semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+		grpcMeter.recordRPC(ctx, duration, stream.recv.Load(), stream.sent.Load(), attrs)
+		return err
+	}, nil
+}
+
+type meterServerStream struct {
+	*serverStream
+	meter *grpcOTELMetrics
+	attrs []attribute.KeyValue
+}
+
+func (s *meterServerStream) RecvMsg(m interface{}) error {
+	err := s.serverStream.RecvMsg(m)
+	if err == nil {
+		s.meter.recordReqSize(s.ctx, m, s.attrs)
+	}
+	return err
+}
+
+func (s *meterServerStream) SendMsg(m interface{}) error {
+	err := s.serverStream.SendMsg(m)
+	if err == nil {
+		s.meter.recordRespSize(s.ctx, m, s.attrs)
+	}
+	return err
+}
+
+// meterAttrs returns metric attributes for the RPC and puts them to the context if configured.
+func meterAttrs(ctx context.Context, fullMethod string, opts otelMeterAttrsOpts) (context.Context, []attribute.KeyValue) {
+	service, method := splitFullMethod(fullMethod)
+	rpcAttrs := []attribute.KeyValue{semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)}
+	attrs := rpcAttrs
+	if opts.AttrsFromCtx {
+		attrs = append(otelbrick.MetricAttrsFromCtx(ctx), rpcAttrs...)
+	}
+	if opts.AttrsToCtx {
+		ctx = otelbrick.AttrsToCtx(ctx, rpcAttrs)
+	}
+	return ctx, attrs
+}
+
+// splitFullMethod splits the full gRPC method name (/package.Service/Method) into service and method names.
+func splitFullMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+func msgSize(m interface{}) int64 {
+	if msg, ok := m.(proto.Message); ok {
+		return int64(proto.Size(msg))
+	}
+	return 0
+}
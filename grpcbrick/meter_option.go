@@ -0,0 +1,122 @@
+package grpcbrick
+
+type otelMeterAttrsOpts struct {
+	AttrsFromCtx bool
+	AttrsToCtx   bool
+}
+
+type otelMeterMetricsOpts struct {
+	Names             OTelMetricNames
+	Duration          bool
+	ReqSize           bool
+	RespSize          bool
+	ReqsPerRPC        bool
+	RespsPerRPC       bool
+	ActiveReqsCounter bool
+}
+
+// OTelMetricNames is a set of metric names. Use it to override the default names.
+type OTelMetricNames struct {
+	DurationHist      string
+	ReqSizeHist       string
+	RespSizeHist      string
+	ReqsPerRPCHist    string
+	RespsPerRPCHist   string
+	ActiveReqsCounter string
+}
+
+type otelMeterOpts struct {
+	Skipper       Skipper
+	StreamSkipper StreamSkipper
+	Metrics       otelMeterMetricsOpts
+	Attrs         otelMeterAttrsOpts
+}
+
+// OTelMeterOption is a function that configures metrics interceptors.
+type OTelMeterOption func(*otelMeterOpts)
+
+func WithoutMeterAttrsToCtx() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Attrs.AttrsToCtx = false
+	}
+}
+
+func WithoutMeterAttrsFromCtx() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Attrs.AttrsFromCtx = false
+	}
+}
+
+func WithoutDurationMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.Duration = false
+	}
+}
+
+func WithoutReqSizeMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.ReqSize = false
+	}
+}
+
+func WithoutRespSizeMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.RespSize = false
+	}
+}
+
+func WithoutReqsPerRPCMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.ReqsPerRPC = false
+	}
+}
+
+func WithoutRespsPerRPCMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.RespsPerRPC = false
+	}
+}
+
+func WithoutActiveReqsCounterMetric() OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Metrics.ActiveReqsCounter = false
+	}
+}
+
+// WithMeterSkipper configures the skipper for the unary interceptor.
+func WithMeterSkipper(skipper Skipper) OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.Skipper = skipper
+	}
+}
+
+// WithMeterStreamSkipper configures the skipper for the stream interceptor.
+func WithMeterStreamSkipper(skipper StreamSkipper) OTelMeterOption {
+	return func(n *otelMeterOpts) {
+		n.StreamSkipper = skipper
+	}
+}
+
+// WithMetricNames overrides the default metric names. Empty names are ignored.
+func WithMetricNames(names OTelMetricNames) OTelMeterOption {
+	return func(opts *otelMeterOpts) {
+		if names.DurationHist != "" {
+			opts.Metrics.Names.DurationHist = names.DurationHist
+		}
+		if names.ReqSizeHist != "" {
+			opts.Metrics.Names.ReqSizeHist = names.ReqSizeHist
+		}
+		if names.RespSizeHist != "" {
+			opts.Metrics.Names.RespSizeHist = names.RespSizeHist
+		}
+		if names.ReqsPerRPCHist != "" {
+			opts.Metrics.Names.ReqsPerRPCHist = names.ReqsPerRPCHist
+		}
+		if names.RespsPerRPCHist != "" {
+			opts.Metrics.Names.RespsPerRPCHist = names.RespsPerRPCHist
+		}
+		if names.ActiveReqsCounter != "" {
+			opts.Metrics.Names.ActiveReqsCounter = names.ActiveReqsCounter
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+func setupTestMeter(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+}
+
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	result := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+func TestOTelMeterUnaryServerInterceptor(t *testing.T) {
+	reader := setupTestMeter(t)
+	intercept, err := OTelMeterUnaryServerInterceptor()
+	require.NoError(t, err)
+
+	ctx := otelbrick.AttrsToCtx(context.Background(), []attribute.KeyValue{attribute.String("tenant", "t1")})
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.Service/Method"}
+	_, err = intercept(ctx, wrapperspb.String("hello"), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Len(t, otelbrick.AttrsFromCtx(ctx), 4)
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	metrics := collectMetrics(t, reader)
+	duration, ok := metrics["rpc.server.duration"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	attrs := duration.DataPoints[0].Attributes
+	assert.True(t, attrs.HasValue(semconv.RPCServiceKey))
+	v, _ := attrs.Value(semconv.RPCServiceKey)
+	assert.Equal(t, "test.v1.Service", v.AsString())
+	v, _ = attrs.Value(semconv.RPCMethodKey)
+	assert.Equal(t, "Method", v.AsString())
+	v, _ = attrs.Value(semconv.RPCGRPCStatusCodeKey)
+	assert.Equal(t, int64(codes.NotFound), v.AsInt64())
+	v, _ = attrs.Value("tenant")
+	assert.Equal(t, "t1", v.AsString())
+
+	reqSize, ok := metrics["rpc.server.request.size"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, reqSize.DataPoints, 1)
+	assert.Equal(t, int64(7), reqSize.DataPoints[0].Sum)
+
+	active, ok := metrics["rpc.server.active_requests"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, active.DataPoints, 1)
+	assert.Equal(t, int64(0), active.DataPoints[0].Value)
+}
+
+func TestOTelMeterStreamServerInterceptor(t *testing.T) {
+	reader := setupTestMeter(t)
+	intercept, err := OTelMeterStreamServerInterceptor(WithoutReqSizeMetric())
+	require.NoError(t, err)
+
+	info := &grpc.StreamServerInfo{FullMethod: "/test.v1.Service/Stream"}
+	ss := &testServerStream{ctx: context.Background()}
+	err = intercept(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		require.NoError(t, stream.RecvMsg(wrapperspb.String("a")))
+		require.NoError(t, stream.SendMsg(wrapperspb.String("b")))
+		require.NoError(t, stream.SendMsg(wrapperspb.String("c")))
+		return nil
+	})
+	require.NoError(t, err)
+
+	metrics := collectMetrics(t, reader)
+	assert.NotContains(t, metrics, "rpc.server.request.size")
+
+	reqs, ok := metrics["rpc.server.requests_per_rpc"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, reqs.DataPoints, 1)
+	assert.Equal(t, int64(1), reqs.DataPoints[0].Sum)
+
+	resps, ok := metrics["rpc.server.responses_per_rpc"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, resps.DataPoints, 1)
+	assert.Equal(t, int64(2), resps.DataPoints[0].Sum)
+
+	respSize, ok := metrics["rpc.server.response.size"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, respSize.DataPoints, 1)
+	assert.Equal(t, uint64(2), respSize.DataPoints[0].Count)
+}
@@ -0,0 +1,99 @@
+package grpcbrick
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/ratelimitbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// RateLimitKeyFunc returns the key to limit the call by.
+type RateLimitKeyFunc func(ctx context.Context, fullMethod string) string
+
+// RateLimitKeyByPeer returns the client IP from the peer address.
+func RateLimitKeyByPeer(ctx context.Context, _ string) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+// RateLimitKeyBySubject returns the JWT subject from the token claims (see TokenClaimsFromCtx).
+// It falls back to RateLimitKeyByPeer for anonymous calls.
+func RateLimitKeyBySubject(ctx context.Context, fullMethod string) string {
+	if sub, err := TokenClaimsFromCtx(ctx).GetSubject(); err == nil && sub != "" {
+		return "sub:" + sub
+	}
+	return RateLimitKeyByPeer(ctx, fullMethod)
+}
+
+// RateLimitUnaryServerInterceptor is an interceptor that limits the calls rate by the key.
+// The calls over the limit are rejected with codes.ResourceExhausted with the retry hint (errdetails.RetryInfo),
+// ratelimit-* headers are sent for the unary calls.
+// If the limiter fails (e.g. the store is unavailable), the error is logged and the call is allowed.
+// If keyFunc is nil, RateLimitKeyByPeer is used.
+func RateLimitUnaryServerInterceptor(limiter ratelimitbrick.Limiter, keyFunc RateLimitKeyFunc, skipper Skipper) grpc.UnaryServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = RateLimitKeyByPeer
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if skipper != nil && skipper(ctx, req, info) {
+			return handler(ctx, req)
+		}
+		res, err := limiter.Allow(ctx, keyFunc(ctx, info.FullMethod))
+		if err != nil {
+			slogbrick.FromCtx(ctx).Error("failed check rate limit", slog.Any("err", err))
+			return handler(ctx, req)
+		}
+		_ = grpc.SetHeader(ctx, rateLimitMD(res))
+		if !res.Allowed {
+			return nil, statusErr(ctx, res.Err())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamServerInterceptor is a stream equivalent of RateLimitUnaryServerInterceptor.
+// The limit is checked once per stream.
+func RateLimitStreamServerInterceptor(limiter ratelimitbrick.Limiter, keyFunc RateLimitKeyFunc, skipper StreamSkipper) grpc.StreamServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = RateLimitKeyByPeer
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if skipper != nil && skipper(ctx, info) {
+			return handler(srv, ss)
+		}
+		res, err := limiter.Allow(ctx, keyFunc(ctx, info.FullMethod))
+		if err != nil {
+			slogbrick.FromCtx(ctx).Error("failed check rate limit", slog.Any("err", err))
+			return handler(srv, ss)
+		}
+		_ = ss.SetHeader(rateLimitMD(res))
+		if !res.Allowed {
+			return statusErr(ctx, res.Err())
+		}
+		return handler(srv, ss)
+	}
+}
+
+func rateLimitMD(res ratelimitbrick.Result) metadata.MD {
+	return metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(res.Limit),
+		"ratelimit-remaining", strconv.Itoa(res.Remaining),
+		"ratelimit-reset", strconv.FormatInt(errbrick.RetryAfterSeconds(res.Reset), 10),
+	)
+}
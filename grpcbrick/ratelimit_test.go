@@ -0,0 +1,38 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/ratelimitbrick"
+)
+
+func TestRateLimitUnaryServerInterceptor(t *testing.T) {
+	limiter := ratelimitbrick.NewTokenBucket(ratelimitbrick.NewMemoryStore(), 0.001, 1)
+	intercept := RateLimitUnaryServerInterceptor(limiter, nil, nil)
+	call := func() error {
+		_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc.Users/Get"},
+			func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+		return err
+	}
+
+	require.NoError(t, call())
+	err := call()
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	var retryInfo *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			retryInfo = ri
+		}
+	}
+	require.NotNil(t, retryInfo)
+	assert.Positive(t, retryInfo.GetRetryDelay().AsDuration())
+}
@@ -0,0 +1,149 @@
+package grpcbrick
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// RecoverOption is a function that configures recover interceptors.
+type RecoverOption func(*recoverOpts)
+
+type recoverOpts struct {
+	printStackWriter io.Writer
+	PanicCounterName string
+	LogStackField    bool
+	PrintStack       bool
+	PanicCounter     bool
+}
+
+// WithRecoverLogStackField allows to add stack trace to logger "stack" attribute.
+func WithRecoverLogStackField(v bool) RecoverOption {
+	return func(opts *recoverOpts) {
+		opts.LogStackField = v
+	}
+}
+
+// WithRecoverPrintStack allows to print stack trace to writer (e.g. os.Stderr).
+func WithRecoverPrintStack(w io.Writer) RecoverOption {
+	return func(opts *recoverOpts) {
+		opts.PrintStack = true
+		opts.printStackWriter = w
+	}
+}
+
+// WithRecoverPanicCounterName overrides the default panic counter metric name.
+func WithRecoverPanicCounterName(name string) RecoverOption {
+	return func(opts *recoverOpts) {
+		opts.PanicCounterName = name
+	}
+}
+
+// WithoutRecoverPanicCounter disables the panic counter metric.
+func WithoutRecoverPanicCounter() RecoverOption {
+	return func(opts *recoverOpts) {
+		opts.PanicCounter = false
+	}
+}
+
+type recoverer struct {
+	panicCounter metric.Int64Counter
+	opts         recoverOpts
+}
+
+func newRecoverer(options []RecoverOption) (*recoverer, error) {
+	opts := recoverOpts{
+		LogStackField:    true,
+		PanicCounter:     true,
+		PanicCounterName: "rpc.server.panics",
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	r := &recoverer{opts: opts}
+	if opts.PanicCounter {
+		counter, err := otel.GetMeterProvider().Meter("bricks/grpcbrick/Recover").
+			Int64Counter(opts.PanicCounterName, metric.WithDescription("The number of recovered panics in gRPC handlers."))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.PanicCounterName, err)
+		}
+		r.panicCounter = counter
+	}
+	return r, nil
+}
+
+// RecoverUnaryServerInterceptor is an interceptor that recovers from panics in unary handlers.
+// The panic is logged, the active OTEL span is marked as errored and codes.Internal is returned to the client.
+func RecoverUnaryServerInterceptor(options ...RecoverOption) (grpc.UnaryServerInterceptor, error) {
+	r, err := newRecoverer(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				err = r.handle(ctx, info.FullMethod, rvr)
+			}
+		}()
+		return handler(ctx, req)
+	}, nil
+}
+
+// RecoverStreamServerInterceptor is a stream equivalent of RecoverUnaryServerInterceptor.
+func RecoverStreamServerInterceptor(options ...RecoverOption) (grpc.StreamServerInterceptor, error) {
+	r, err := newRecoverer(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				err = r.handle(ss.Context(), info.FullMethod, rvr)
+			}
+		}()
+		return handler(srv, ss)
+	}, nil
+}
+
+func (r *recoverer) handle(ctx context.Context, fullMethod string, rvr interface{}) error {
+	stackTrace := string(debug.Stack())
+	reqLogger := slogbrick.FromCtx(ctx)
+
+	attrs := []interface{}{
+		slog.Any("err", rvr),
+	}
+	if r.opts.LogStackField {
+		attrs = append(attrs, slog.String("stack", stackTrace))
+	}
+	reqLogger.With(attrs...).Error("grpc handler panicked and recovered")
+
+	if r.opts.PrintStack {
+		if _, err := fmt.Fprintf(r.opts.printStackWriter, "%v:\n%s\n", rvr, stackTrace); err != nil {
+			reqLogger.Error("failed print stack trace", slog.Any("err", err))
+		}
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(fmt.Errorf("panic: %v", rvr))
+	span.SetStatus(otelcodes.Error, "panic recovered")
+
+	if r.opts.PanicCounter {
+		service, method := splitFullMethod(fullMethod)
+		r.panicCounter.Add(ctx, 1, metric.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)))
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,68 @@
+package grpcbrick
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoverUnaryServerInterceptor(t *testing.T) {
+	reader := setupTestMeter(t)
+	buf := &bytes.Buffer{}
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+	stackBuf := &bytes.Buffer{}
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "rpc")
+
+	intercept, err := RecoverUnaryServerInterceptor(WithRecoverLogStackField(false), WithRecoverPrintStack(stackBuf))
+	require.NoError(t, err)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.Service/Method"}
+	_, err = intercept(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	span.End()
+
+	assert.Equal(t, status.Error(grpccodes.Internal, "internal error"), err)
+	assert.Contains(t, buf.String(), "grpc handler panicked and recovered")
+	assert.NotContains(t, buf.String(), "stack=")
+	assert.Contains(t, stackBuf.String(), "boom:\ngoroutine")
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	counter, ok := collectMetrics(t, reader)["rpc.server.panics"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, counter.DataPoints, 1)
+	assert.Equal(t, int64(1), counter.DataPoints[0].Value)
+}
+
+func TestRecoverStreamServerInterceptor(t *testing.T) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	intercept, err := RecoverStreamServerInterceptor(WithoutRecoverPanicCounter())
+	require.NoError(t, err)
+
+	ss := &testServerStream{ctx: context.Background()}
+	err = intercept(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		panic("boom")
+	})
+	assert.Equal(t, grpccodes.Internal, status.Code(err))
+
+	err = intercept(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
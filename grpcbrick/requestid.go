@@ -0,0 +1,64 @@
+package grpcbrick
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+// RequestIDUnaryServerInterceptor is an interceptor that reads the request ID from the x-request-id metadata
+// or generates a new one, binds it to the context and the logger (see requestidbrick.Bind)
+// and sends it back in the header metadata.
+// Put it after SlogCtxUnaryServerInterceptor, so the request logger gets the request ID.
+func RequestIDUnaryServerInterceptor(options ...requestidbrick.Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := requestidbrick.Bind(ctx, incomingRequestID(ctx), options...)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestidbrick.MetadataKey, id))
+		return handler(ctx, req)
+	}
+}
+
+// RequestIDStreamServerInterceptor is a stream equivalent of RequestIDUnaryServerInterceptor.
+func RequestIDStreamServerInterceptor(options ...requestidbrick.Option) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := requestidbrick.Bind(ss.Context(), incomingRequestID(ss.Context()), options...)
+		_ = ss.SetHeader(metadata.Pairs(requestidbrick.MetadataKey, id))
+		return handler(srv, wrapServerStream(ctx, ss))
+	}
+}
+
+// RequestIDUnaryClientInterceptor is an interceptor that forwards the request ID from the context
+// (see requestidbrick.FromCtx) in the x-request-id metadata.
+func RequestIDUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// RequestIDStreamClientInterceptor is a stream equivalent of RequestIDUnaryClientInterceptor.
+func RequestIDStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func incomingRequestID(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, requestidbrick.MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	id := requestidbrick.FromCtx(ctx)
+	if id == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(requestidbrick.MetadataKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, requestidbrick.MetadataKey, id)
+}
@@ -0,0 +1,53 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+func TestRequestIDUnaryServerInterceptor(t *testing.T) {
+	intercept := RequestIDUnaryServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestidbrick.MetadataKey, "req-42"))
+
+	var gotID string
+	_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		gotID = requestidbrick.FromCtx(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req-42", gotID)
+
+	_, err = intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		gotID = requestidbrick.FromCtx(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, requestidbrick.Valid(gotID))
+	assert.NotEqual(t, "req-42", gotID)
+}
+
+func TestRequestIDUnaryClientInterceptor(t *testing.T) {
+	intercept := RequestIDUnaryClientInterceptor()
+	var gotMD metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		gotMD, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	require.NoError(t, intercept(requestidbrick.ToCtx(context.Background(), "req-42"), "/svc/M", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"req-42"}, gotMD.Get(requestidbrick.MetadataKey))
+
+	ctx := metadata.AppendToOutgoingContext(requestidbrick.ToCtx(context.Background(), "req-42"), requestidbrick.MetadataKey, "explicit")
+	require.NoError(t, intercept(ctx, "/svc/M", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"explicit"}, gotMD.Get(requestidbrick.MetadataKey))
+
+	require.NoError(t, intercept(context.Background(), "/svc/M", nil, nil, nil, invoker))
+	assert.Empty(t, gotMD.Get(requestidbrick.MetadataKey))
+}
@@ -0,0 +1,282 @@
+package grpcbrick
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/demeero/bricks/configbrick"
+	"github.com/demeero/bricks/healthbrick"
+	"github.com/demeero/bricks/requestidbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// ServerOption is a function that configures the server.
+type ServerOption func(*serverOpts)
+
+type serverOpts struct {
+	listener           net.Listener
+	healthReg          *healthbrick.Registry
+	grpcOpts           []grpc.ServerOption
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	recoverOpts        []RecoverOption
+	accessLogSkipper   Skipper
+	accessStreamSkip   StreamSkipper
+	errSkipper         Skipper
+	errStreamSkipper   StreamSkipper
+	reqIDOpts          []requestidbrick.Option
+	withoutTrace       bool
+	withoutHealth      bool
+	withoutReqID       bool
+}
+
+// WithServerListener sets the listener to serve on instead of listening on configbrick.GRPC.Port.
+func WithServerListener(ln net.Listener) ServerOption {
+	return func(opts *serverOpts) {
+		opts.listener = ln
+	}
+}
+
+// WithServerOptions adds the options to create grpc.Server with, e.g. credentials or keepalive params.
+func WithServerOptions(options ...grpc.ServerOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.grpcOpts = append(opts.grpcOpts, options...)
+	}
+}
+
+// WithServerUnaryInterceptors adds the unary interceptors to the end of the standard chain (right before the handler).
+func WithServerUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(opts *serverOpts) {
+		opts.unaryInterceptors = append(opts.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithServerStreamInterceptors adds the stream interceptors to the end of the standard chain (right before the handler).
+func WithServerStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) ServerOption {
+	return func(opts *serverOpts) {
+		opts.streamInterceptors = append(opts.streamInterceptors, interceptors...)
+	}
+}
+
+// WithServerRecoverOpts sets the options for the recovery interceptors.
+func WithServerRecoverOpts(options ...RecoverOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.recoverOpts = append(opts.recoverOpts, options...)
+	}
+}
+
+// WithServerAccessLogSkippers sets the skippers for the access log interceptors.
+func WithServerAccessLogSkippers(skipper Skipper, streamSkipper StreamSkipper) ServerOption {
+	return func(opts *serverOpts) {
+		opts.accessLogSkipper = skipper
+		opts.accessStreamSkip = streamSkipper
+	}
+}
+
+// WithServerErrSkippers sets the skippers for the error mapping interceptors.
+func WithServerErrSkippers(skipper Skipper, streamSkipper StreamSkipper) ServerOption {
+	return func(opts *serverOpts) {
+		opts.errSkipper = skipper
+		opts.errStreamSkipper = streamSkipper
+	}
+}
+
+// WithoutServerTrace disables adding OTEL trace attributes to the request logger.
+func WithoutServerTrace() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutTrace = true
+	}
+}
+
+// WithServerRequestIDOpts sets the options for the request ID interceptors.
+func WithServerRequestIDOpts(options ...requestidbrick.Option) ServerOption {
+	return func(opts *serverOpts) {
+		opts.reqIDOpts = append(opts.reqIDOpts, options...)
+	}
+}
+
+// WithoutServerRequestID disables the request ID interceptors.
+func WithoutServerRequestID() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutReqID = true
+	}
+}
+
+// WithServerHealthRegistry registers HealthServer backed by the registry instead of the default grpc.health.v1 service.
+func WithServerHealthRegistry(reg *healthbrick.Registry) ServerOption {
+	return func(opts *serverOpts) {
+		opts.healthReg = reg
+	}
+}
+
+// WithoutServerHealth disables registering the grpc.health.v1 service.
+func WithoutServerHealth() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutHealth = true
+	}
+}
+
+// Server is a gRPC server built from configbrick.GRPC with the standard interceptors chain.
+// It implements grpc.ServiceRegistrar, so services can be registered directly on it.
+type Server struct {
+	srv      *grpc.Server
+	health   *health.Server
+	listener net.Listener
+	cfg      configbrick.GRPC
+}
+
+// NewServer creates a new gRPC server.
+// The interceptors are chained in the following order (from the outermost):
+// logger ctx, request ID, recovery, access log (enabled by cfg.AccessLog with cfg.AccessLogLevel), error mapping
+// and then the interceptors added via WithServerUnaryInterceptors and WithServerStreamInterceptors.
+// The grpc.health.v1 service is registered unless WithoutServerHealth is used,
+// the reflection service is registered if cfg.EnableReflection is set.
+func NewServer(cfg configbrick.GRPC, options ...ServerOption) (*Server, error) {
+	opts := serverOpts{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	recoverUnary, err := RecoverUnaryServerInterceptor(opts.recoverOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed create recover unary interceptor: %w", err)
+	}
+	recoverStream, err := RecoverStreamServerInterceptor(opts.recoverOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed create recover stream interceptor: %w", err)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{SlogCtxUnaryServerInterceptor(!opts.withoutTrace)}
+	stream := []grpc.StreamServerInterceptor{SlogCtxStreamServerInterceptor(!opts.withoutTrace)}
+	if !opts.withoutReqID {
+		unary = append(unary, RequestIDUnaryServerInterceptor(opts.reqIDOpts...))
+		stream = append(stream, RequestIDStreamServerInterceptor(opts.reqIDOpts...))
+	}
+	unary = append(unary, recoverUnary)
+	stream = append(stream, recoverStream)
+	if cfg.AccessLog {
+		lvl := slogbrick.ParseLevel(cfg.AccessLogLevel, slog.LevelDebug)
+		unary = append(unary, SlogUnaryServerInterceptor(lvl, opts.accessLogSkipper))
+		stream = append(stream, SlogStreamServerInterceptor(lvl, opts.accessStreamSkip))
+	}
+	unary = append(unary, ErrUnaryServerInterceptor(opts.errSkipper))
+	stream = append(stream, ErrStreamServerInterceptor(opts.errStreamSkipper))
+	unary = append(unary, opts.unaryInterceptors...)
+	stream = append(stream, opts.streamInterceptors...)
+
+	grpcOpts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}, opts.grpcOpts...)
+	s := &Server{
+		srv:      grpc.NewServer(grpcOpts...),
+		listener: opts.listener,
+		cfg:      cfg,
+	}
+	switch {
+	case opts.withoutHealth:
+	case opts.healthReg != nil:
+		healthpb.RegisterHealthServer(s.srv, NewHealthServer(opts.healthReg, 0))
+	default:
+		s.health = health.NewServer()
+		healthpb.RegisterHealthServer(s.srv, s.health)
+	}
+	if cfg.EnableReflection {
+		reflection.Register(s.srv)
+	}
+	return s, nil
+}
+
+// RegisterService registers a service and its implementation to the underlying grpc.Server.
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.srv.RegisterService(desc, impl)
+}
+
+// GRPCServer returns the underlying grpc.Server.
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.srv
+}
+
+// Health returns the health server to manage the services serving status.
+// It returns nil if the health service is disabled via WithoutServerHealth or WithServerHealthRegistry is used.
+func (s *Server) Health() *health.Server {
+	return s.health
+}
+
+// Run starts the server and blocks until the context is done or SIGINT/SIGTERM is received.
+// Then it gracefully stops the server within configbrick.GRPC.ShutdownTimeout (see Shutdown).
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down grpc server", slog.Duration("timeout", s.cfg.ShutdownTimeout))
+	return s.Shutdown(context.Background())
+}
+
+// Serve listens on configbrick.GRPC.Port (or the listener set via WithServerListener) and serves the requests.
+// It blocks until the server is stopped, in that case nil is returned.
+func (s *Server) Serve() error {
+	ln := s.listener
+	if ln == nil {
+		addr := ":" + strconv.Itoa(s.cfg.Port)
+		var err error
+		if ln, err = net.Listen("tcp", addr); err != nil {
+			return fmt.Errorf("failed listen %s: %w", addr, err)
+		}
+	}
+	slog.Info("starting grpc server", slog.String("addr", ln.Addr().String()))
+	if err := s.srv.Serve(ln); err != nil {
+		return fmt.Errorf("failed serve grpc: %w", err)
+	}
+	return nil
+}
+
+// Shutdown marks the server as not serving in the health service and gracefully stops it.
+// If the graceful stop doesn't finish within configbrick.GRPC.ShutdownTimeout or the context is done,
+// the server is stopped forcefully and the error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		slog.Info("grpc server stopped")
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-stopped
+		return fmt.Errorf("failed gracefully stop grpc server in %s: %w", s.cfg.ShutdownTimeout, ctx.Err())
+	}
+}
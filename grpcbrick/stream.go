@@ -0,0 +1,102 @@
+package grpcbrick
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// StreamSkipper defines a function to skip stream interceptors.
+// Returning true skips processing the interceptor.
+type StreamSkipper func(ctx context.Context, info *grpc.StreamServerInfo) bool
+
+// serverStream wraps grpc.ServerStream to override its context and to count the messages passing through it.
+type serverStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv atomic.Int64
+	sent atomic.Int64
+}
+
+func wrapServerStream(ctx context.Context, ss grpc.ServerStream) *serverStream {
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recv.Add(1)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+// SlogCtxStreamServerInterceptor is a stream equivalent of SlogCtxUnaryServerInterceptor.
+// It puts a request logger to the stream context, so handlers can obtain it via slogbrick.FromCtx(stream.Context()).
+func SlogCtxStreamServerInterceptor(trace bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		reqLogger := slog.Default().With(slog.String("grpc_method", info.FullMethod))
+		if trace {
+			reqLogger = slogbrick.WithOTELTrace(ctx, reqLogger)
+		}
+		return handler(srv, wrapServerStream(slogbrick.ToCtx(ctx, reqLogger), ss))
+	}
+}
+
+// SlogStreamServerInterceptor is a stream equivalent of SlogUnaryServerInterceptor.
+// It logs stream opening and closing with the number of received/sent messages and the stream duration.
+func SlogStreamServerInterceptor(lvl slog.Level, skipper StreamSkipper) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if skipper != nil && skipper(ctx, info) {
+			return handler(srv, ss)
+		}
+		reqLogger := slogbrick.FromCtx(ctx)
+		reqLogger.Log(ctx, lvl, "incoming grpc stream",
+			slog.Bool("grpc_client_stream", info.IsClientStream),
+			slog.Bool("grpc_server_stream", info.IsServerStream))
+
+		startTime := time.Now().UTC()
+		stream := wrapServerStream(ctx, ss)
+		err := handler(srv, stream)
+		if err != nil {
+			reqLogger = reqLogger.With(slog.Any("err", err))
+		}
+
+		reqLogger.Log(ctx, lvl, "outgoing grpc stream closed",
+			slog.Int64("grpc_req_duration_ms", time.Since(startTime).Milliseconds()),
+			slog.String("grpc_resp_code", status.Code(err).String()),
+			slog.Int64("grpc_msgs_received", stream.recv.Load()),
+			slog.Int64("grpc_msgs_sent", stream.sent.Load()))
+		return err
+	}
+}
+
+// ErrStreamServerInterceptor is a stream equivalent of ErrUnaryServerInterceptor.
+// It translates errbrick errors returned by the stream handler into gRPC status errors.
+func ErrStreamServerInterceptor(skipper StreamSkipper) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if skipper != nil && skipper(ss.Context(), info) {
+			return handler(srv, ss)
+		}
+		return statusErr(ss.Context(), handler(srv, ss))
+	}
+}
@@ -0,0 +1,105 @@
+package grpcbrick
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func (s *testServerStream) RecvMsg(interface{}) error {
+	return nil
+}
+
+func TestErrStreamServerInterceptor(t *testing.T) {
+	var tests = []struct {
+		name    string
+		skipper StreamSkipper
+		err     error
+		wantErr error
+	}{
+		{
+			name:    "with-skipper",
+			skipper: func(ctx context.Context, info *grpc.StreamServerInfo) bool { return true },
+			err:     errors.New("test"),
+			wantErr: errors.New("test"),
+		},
+		{
+			name:    "unknown-error",
+			err:     errors.New("test"),
+			wantErr: status.Error(codes.Internal, "internal error"),
+		},
+		{
+			name:    "ErrNotFound",
+			err:     errbrick.ErrNotFound,
+			wantErr: status.Error(codes.NotFound, errbrick.ErrNotFound.Error()),
+		},
+		{
+			name:    "grpc-status",
+			err:     status.Error(codes.Aborted, "aborted"),
+			wantErr: status.Error(codes.Aborted, "aborted"),
+		},
+		{
+			name: "no-error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intercept := ErrStreamServerInterceptor(tt.skipper)
+			ss := &testServerStream{ctx: context.Background()}
+			err := intercept(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+				return tt.err
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestSlogStreamServerInterceptors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+
+	ctxIntercept := SlogCtxStreamServerInterceptor(false)
+	logIntercept := SlogStreamServerInterceptor(slog.LevelInfo, nil)
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream", IsServerStream: true}
+	ss := &testServerStream{ctx: context.Background()}
+
+	err := ctxIntercept(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return logIntercept(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			require.NoError(t, stream.RecvMsg(nil))
+			require.NoError(t, stream.SendMsg(nil))
+			require.NoError(t, stream.SendMsg(nil))
+			slogbrick.FromCtx(stream.Context()).Info("handler")
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "msg=\"incoming grpc stream\" grpc_method=/test.Service/Stream")
+	assert.Contains(t, out, "msg=handler grpc_method=/test.Service/Stream")
+	assert.Contains(t, out, "grpc_resp_code=OK grpc_msgs_received=1 grpc_msgs_sent=2")
+}
@@ -0,0 +1,195 @@
+package grpcbrick
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// MethodSkipper defines a function to skip both unary and stream interceptors by the full method name.
+// Returning true skips processing the interceptor.
+type MethodSkipper func(ctx context.Context, fullMethod string) bool
+
+// SkipHealthAndReflection is a MethodSkipper that skips the gRPC health checking and reflection services.
+func SkipHealthAndReflection(_ context.Context, fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.v1.ServerReflection/") ||
+		strings.HasPrefix(fullMethod, "/grpc.reflection.v1alpha.ServerReflection/")
+}
+
+type TokenClaimsOpts func(*tokenClaimsOpts)
+
+type tokenClaimsOpts struct {
+	Validator     *jwtbrick.Validator
+	Skipper       MethodSkipper
+	KeyFuncOpts   keyfunc.Options
+	ValidatorOpts []jwtbrick.ValidatorOption
+	JWKSURL       string
+	Header        string
+	HeaderPrefix  string
+	ErrCode       codes.Code
+}
+
+// WithTokenClaimsErrCode sets the status code for the interceptor in the case of error.
+// codes.Unauthenticated is used by default.
+func WithTokenClaimsErrCode(code codes.Code) TokenClaimsOpts {
+	return func(opts *tokenClaimsOpts) {
+		opts.ErrCode = code
+	}
+}
+
+// WithTokenClaimsJWKSURL sets the URL to retrieve JWKS from.
+func WithTokenClaimsJWKSURL(url string) TokenClaimsOpts {
+	return func(opts *tokenClaimsOpts) {
+		opts.JWKSURL = url
+	}
+}
+
+// WithTokenClaimsHeader sets the metadata key to retrieve the token from.
+// "authorization" is used by default.
+func WithTokenClaimsHeader(header string) TokenClaimsOpts {
+	return func(opts *tokenClaimsOpts) {
+		opts.Header = strings.ToLower(header)
+	}
+}
+
+// WithTokenClaimsHeaderPrefix sets the prefix for the token in the metadata value.
+// "bearer" is used by default.
+func WithTokenClaimsHeaderPrefix(prefix string) TokenClaimsOpts {
+	return func(opts *tokenClaimsOpts) {
+		opts.HeaderPrefix = prefix
+	}
+}
+
+// WithTokenClaimsKeyFuncOpts sets the options for the keyfunc.
+func WithTokenClaimsKeyFuncOpts(opts keyfunc.Options) TokenClaimsOpts {
+	return func(o *tokenClaimsOpts) {
+		o.KeyFuncOpts = opts
+	}
+}
+
+// WithTokenClaimsValidator sets the validator to verify tokens with.
+// If set, other validation options (JWKS URL, keyfunc and validator options) are ignored.
+func WithTokenClaimsValidator(v *jwtbrick.Validator) TokenClaimsOpts {
+	return func(o *tokenClaimsOpts) {
+		o.Validator = v
+	}
+}
+
+// WithTokenClaimsValidatorOpts sets the options to create the validator with,
+// e.g. static keys, HMAC secret, issuer, audience etc.
+func WithTokenClaimsValidatorOpts(opts ...jwtbrick.ValidatorOption) TokenClaimsOpts {
+	return func(o *tokenClaimsOpts) {
+		o.ValidatorOpts = append(o.ValidatorOpts, opts...)
+	}
+}
+
+// WithTokenClaimsSkipper sets the skipper for the interceptors, e.g. SkipHealthAndReflection.
+func WithTokenClaimsSkipper(skipper MethodSkipper) TokenClaimsOpts {
+	return func(o *tokenClaimsOpts) {
+		o.Skipper = skipper
+	}
+}
+
+var defaultTokenClaimsOpts = tokenClaimsOpts{
+	ErrCode:      codes.Unauthenticated,
+	Header:       "authorization",
+	HeaderPrefix: "bearer",
+}
+
+// TokenClaimsUnaryServerInterceptor is an interceptor that extracts the JWT token from the incoming metadata,
+// validates it and puts the claims to the context (see TokenClaimsFromCtx).
+// If the validator can't be created (e.g. no keys configured or JWKS can't be loaded), the error is logged
+// and the interceptor rejects all requests - unsigned or unverified tokens are never accepted.
+func TokenClaimsUnaryServerInterceptor(options ...TokenClaimsOpts) grpc.UnaryServerInterceptor {
+	authenticate := tokenClaimsAuthenticator(options...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// TokenClaimsStreamServerInterceptor is a stream equivalent of TokenClaimsUnaryServerInterceptor.
+func TokenClaimsStreamServerInterceptor(options ...TokenClaimsOpts) grpc.StreamServerInterceptor {
+	authenticate := tokenClaimsAuthenticator(options...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, wrapServerStream(ctx, ss))
+	}
+}
+
+func tokenClaimsAuthenticator(options ...TokenClaimsOpts) func(ctx context.Context, fullMethod string) (context.Context, error) {
+	opts := defaultTokenClaimsOpts
+	for _, opt := range options {
+		opt(&opts)
+	}
+	validator, validatorErr := tokenClaimsValidator(opts)
+	if validatorErr != nil {
+		slog.Error("failed create JWT validator - all requests will be rejected", slog.Any("err", validatorErr))
+	}
+	return func(ctx context.Context, fullMethod string) (context.Context, error) {
+		if opts.Skipper != nil && opts.Skipper(ctx, fullMethod) {
+			return ctx, nil
+		}
+		if validatorErr != nil {
+			return nil, status.Error(opts.ErrCode, "token validation is unavailable")
+		}
+		jwtToken, err := retrieveJWT(ctx, opts)
+		if err != nil {
+			return nil, status.Error(opts.ErrCode, err.Error())
+		}
+		claims, err := validator.Validate(jwtToken)
+		if err != nil {
+			slogbrick.FromCtx(ctx).Debug("invalid token", slog.Any("err", err))
+			return nil, status.Error(opts.ErrCode, "invalid token")
+		}
+		return jwtbrick.ClaimsToCtx(ctx, claims), nil
+	}
+}
+
+func tokenClaimsValidator(opts tokenClaimsOpts) (*jwtbrick.Validator, error) {
+	if opts.Validator != nil {
+		return opts.Validator, nil
+	}
+	validatorOpts := opts.ValidatorOpts
+	if opts.JWKSURL != "" {
+		validatorOpts = append(validatorOpts, jwtbrick.WithJWKSURL(opts.JWKSURL), jwtbrick.WithKeyFuncOpts(opts.KeyFuncOpts))
+	}
+	return jwtbrick.NewValidator(validatorOpts...)
+}
+
+// TokenClaimsFromCtx returns the token claims from the context.
+// It's a shortcut for jwtbrick.ClaimsFromCtx, use jwtbrick.TypedClaimsFromCtx to get typed claims.
+func TokenClaimsFromCtx(ctx context.Context) jwt.MapClaims {
+	return jwtbrick.ClaimsFromCtx(ctx)
+}
+
+// retrieveJWT returns the token string from the incoming metadata.
+func retrieveJWT(ctx context.Context, opts tokenClaimsOpts) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(opts.Header)
+	if len(values) == 0 || values[0] == "" {
+		return "", errors.New("authorization metadata is empty")
+	}
+	if !strings.HasPrefix(strings.ToLower(values[0]), strings.ToLower(opts.HeaderPrefix)) {
+		return "", errors.New("invalid authorization metadata format")
+	}
+	return strings.TrimSpace(values[0][len(opts.HeaderPrefix):]), nil
+}
@@ -0,0 +1,129 @@
+package grpcbrick
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestTokenClaimsUnaryServerInterceptor(t *testing.T) {
+	secret := []byte("secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	require.NoError(t, err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	hmac := WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret))
+
+	tests := []struct {
+		name        string
+		method      string
+		auth        string
+		options     []TokenClaimsOpts
+		wantCode    codes.Code
+		wantSubject string
+	}{
+		{
+			name:        "valid",
+			method:      "/svc.Users/Get",
+			auth:        "Bearer " + signed,
+			options:     []TokenClaimsOpts{hmac},
+			wantCode:    codes.OK,
+			wantSubject: "user-1",
+		},
+		{
+			name:     "unsigned",
+			method:   "/svc.Users/Get",
+			auth:     "bearer " + unsigned,
+			options:  []TokenClaimsOpts{hmac},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "no-metadata",
+			method:   "/svc.Users/Get",
+			options:  []TokenClaimsOpts{hmac},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid-prefix",
+			method:   "/svc.Users/Get",
+			auth:     "basic " + signed,
+			options:  []TokenClaimsOpts{hmac},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "no-keys",
+			method:   "/svc.Users/Get",
+			auth:     "bearer " + signed,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "custom-code",
+			method:   "/svc.Users/Get",
+			auth:     "bearer " + unsigned,
+			options:  []TokenClaimsOpts{hmac, WithTokenClaimsErrCode(codes.PermissionDenied)},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "skip-health",
+			method:   "/grpc.health.v1.Health/Check",
+			options:  []TokenClaimsOpts{hmac, WithTokenClaimsSkipper(SkipHealthAndReflection)},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.auth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.auth))
+			}
+			var subject string
+			intercept := TokenClaimsUnaryServerInterceptor(tt.options...)
+			_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				subject, _ = TokenClaimsFromCtx(ctx).GetSubject()
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantSubject, subject)
+		})
+	}
+}
+
+func TestTokenClaimsStreamServerInterceptor(t *testing.T) {
+	secret := []byte("secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer "+signed))
+	intercept := TokenClaimsStreamServerInterceptor(WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret)))
+	var subject string
+	err = intercept(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/svc.Users/Watch"}, func(srv interface{}, ss grpc.ServerStream) error {
+		subject, _ = TokenClaimsFromCtx(ss.Context()).GetSubject()
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", subject)
+
+	err = intercept(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/svc.Users/Watch"}, func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,217 @@
+// Package healthbrick provides a registry of health checks used for liveness and readiness probes.
+package healthbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is a status of a check or the whole report.
+type Status string
+
+const (
+	// StatusUp means the check passed.
+	StatusUp Status = "up"
+	// StatusDegraded means some non-critical checks failed.
+	StatusDegraded Status = "degraded"
+	// StatusDown means the check (or any critical check in the report) failed.
+	StatusDown Status = "down"
+)
+
+// Checker checks the health of a component. Returning an error marks the component as down.
+type Checker func(ctx context.Context) error
+
+// Result is a result of a single check.
+type Result struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	Status     Status    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Critical   bool      `json:"critical"`
+}
+
+// Report is an aggregated result of the checks.
+type Report struct {
+	Checks map[string]Result `json:"checks,omitempty"`
+	Status Status            `json:"status"`
+}
+
+// CheckOption is a function that configures a check.
+type CheckOption func(*check)
+
+// WithTimeout sets the timeout for the check.
+// 5s is used by default.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// WithNonCritical marks the check as non-critical, so its failure degrades the report instead of marking it as down.
+func WithNonCritical() CheckOption {
+	return func(c *check) {
+		c.critical = false
+	}
+}
+
+// WithCacheTTL sets the duration to cache the check result for.
+// It protects the checked components from frequent probes. The results aren't cached by default.
+func WithCacheTTL(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.cacheTTL = d
+	}
+}
+
+// WithLiveness includes the check to the liveness report as well.
+// By default, the checks are included to the readiness report only,
+// because a failed dependency usually shouldn't cause the restart of the application.
+func WithLiveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+type check struct {
+	checker  Checker
+	last     Result
+	name     string
+	timeout  time.Duration
+	cacheTTL time.Duration
+	mu       sync.Mutex
+	critical bool
+	liveness bool
+}
+
+func (c *check) run(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cacheTTL > 0 && !c.last.CheckedAt.IsZero() && time.Since(c.last.CheckedAt) < c.cacheTTL {
+		return c.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	startTime := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.checker(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	c.last = Result{
+		CheckedAt:  startTime.UTC(),
+		Status:     StatusUp,
+		DurationMS: time.Since(startTime).Milliseconds(),
+		Critical:   c.critical,
+	}
+	if err != nil {
+		c.last.Status = StatusDown
+		c.last.Error = err.Error()
+	}
+	return c.last
+}
+
+// ErrCheckNotFound is returned when the check isn't registered.
+var ErrCheckNotFound = errors.New("health check not found")
+
+// Registry is a registry of health checks.
+type Registry struct {
+	checks []*check
+	mu     sync.RWMutex
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the named check. Registering the check with the same name replaces the previous one.
+// The checks are critical and have 5s timeout by default.
+func (r *Registry) Register(name string, checker Checker, options ...CheckOption) {
+	c := &check{name: name, checker: checker, timeout: 5 * time.Second, critical: true}
+	for _, opt := range options {
+		opt(c)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.checks {
+		if existing.name == name {
+			r.checks[i] = c
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+}
+
+// Live runs the liveness checks (see WithLiveness).
+// The report without checks is up - it means the process is able to serve the request.
+func (r *Registry) Live(ctx context.Context) Report {
+	return r.report(ctx, func(c *check) bool { return c.liveness })
+}
+
+// Ready runs all the checks concurrently.
+func (r *Registry) Ready(ctx context.Context) Report {
+	return r.report(ctx, func(*check) bool { return true })
+}
+
+// CheckOne runs the named check.
+func (r *Registry) CheckOne(ctx context.Context, name string) (Result, error) {
+	r.mu.RLock()
+	var found *check
+	for _, c := range r.checks {
+		if c.name == name {
+			found = c
+			break
+		}
+	}
+	r.mu.RUnlock()
+	if found == nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+	}
+	return found.run(ctx), nil
+}
+
+func (r *Registry) report(ctx context.Context, filter func(*check) bool) Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if filter(c) {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		res := results[i]
+		report.Checks[c.name] = res
+		if res.Status != StatusDown {
+			continue
+		}
+		if res.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
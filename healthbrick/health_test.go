@@ -0,0 +1,142 @@
+package healthbrick
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func up(context.Context) error { return nil }
+
+func down(context.Context) error { return errors.New("connection refused") }
+
+func TestRegistry_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		register   func(r *Registry)
+		wantStatus Status
+	}{
+		{
+			name:       "no-checks",
+			register:   func(*Registry) {},
+			wantStatus: StatusUp,
+		},
+		{
+			name: "all-up",
+			register: func(r *Registry) {
+				r.Register("db", up)
+				r.Register("cache", up, WithNonCritical())
+			},
+			wantStatus: StatusUp,
+		},
+		{
+			name: "non-critical-down",
+			register: func(r *Registry) {
+				r.Register("db", up)
+				r.Register("cache", down, WithNonCritical())
+			},
+			wantStatus: StatusDegraded,
+		},
+		{
+			name: "critical-down",
+			register: func(r *Registry) {
+				r.Register("db", down)
+				r.Register("cache", down, WithNonCritical())
+			},
+			wantStatus: StatusDown,
+		},
+		{
+			name: "replaced",
+			register: func(r *Registry) {
+				r.Register("db", down)
+				r.Register("db", up)
+			},
+			wantStatus: StatusUp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			tt.register(r)
+			assert.Equal(t, tt.wantStatus, r.Ready(context.Background()).Status)
+		})
+	}
+}
+
+func TestRegistry_Live(t *testing.T) {
+	r := NewRegistry()
+	r.Register("db", down)
+	r.Register("event-loop", up, WithLiveness())
+
+	report := r.Live(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Len(t, report.Checks, 1)
+	assert.Contains(t, report.Checks, "event-loop")
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	r := NewRegistry()
+	r.Register("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, WithTimeout(10*time.Millisecond))
+
+	res, err := r.CheckOne(context.Background(), "stuck")
+	require.NoError(t, err)
+	assert.Equal(t, StatusDown, res.Status)
+	assert.Contains(t, res.Error, "timed out")
+}
+
+func TestRegistry_CacheTTL(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRegistry()
+	r.Register("db", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}, WithCacheTTL(time.Hour))
+
+	r.Ready(context.Background())
+	r.Ready(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRegistry_CheckOne_NotFound(t *testing.T) {
+	_, err := NewRegistry().CheckOne(context.Background(), "db")
+	assert.ErrorIs(t, err, ErrCheckNotFound)
+}
+
+func TestRegistry_RegisterStatusGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(prev) })
+
+	r := NewRegistry()
+	r.Register("db", up)
+	r.Register("cache", down, WithNonCritical())
+	reg, err := r.RegisterStatusGauge("")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Unregister() })
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "health.check.status", m.Name)
+
+	got := map[string]int64{}
+	for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+		check, _ := dp.Attributes.Value("health.check")
+		got[check.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"db": 1, "cache": 0}, got)
+}
@@ -0,0 +1,40 @@
+package healthbrick
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterStatusGauge registers the OTEL observable gauge with the status of each check (1 is up, 0 is down).
+// The checks are run on each collection, so consider WithCacheTTL for the expensive checks.
+// Use the returned registration to unregister the gauge.
+func (r *Registry) RegisterStatusGauge(name string) (metric.Registration, error) {
+	if name == "" {
+		name = "health.check.status"
+	}
+	meter := otel.GetMeterProvider().Meter("bricks/healthbrick")
+	gauge, err := meter.Int64ObservableGauge(name, metric.WithDescription("The status of the health check (1 is up, 0 is down)"))
+	if err != nil {
+		return nil, fmt.Errorf("failed create %s metric: %w", name, err)
+	}
+	reg, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for checkName, res := range r.Ready(ctx).Checks {
+			var v int64
+			if res.Status == StatusUp {
+				v = 1
+			}
+			o.ObserveInt64(gauge, v, metric.WithAttributes(
+				attribute.String("health.check", checkName),
+				attribute.Bool("health.critical", res.Critical)))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		return nil, fmt.Errorf("failed register %s callback: %w", name, err)
+	}
+	return reg, nil
+}
@@ -0,0 +1,22 @@
+package httpbrick
+
+import (
+	"net/http"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+// AuthorizeMW is a middleware that checks the token claims against the policies (see jwtbrick.Authorize).
+// It's expected that the claims are put to the request context by TokenClaimsMW.
+// Errors are written via WriteError, so errbrick.ErrUnauthenticated results in 401 and errbrick.ErrForbidden in 403.
+func AuthorizeMW(policies ...jwtbrick.Policy) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := jwtbrick.Authorize(req.Context(), policies...); err != nil {
+				WriteError(w, req, err)
+				return
+			}
+			h.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestAuthorizeMW(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode int
+	}{
+		{
+			name:     "AuthorizeMW_WithGrantedScope_CallsHandler",
+			ctx:      jwtbrick.ClaimsToCtx(context.Background(), jwt.MapClaims{"scope": "users:read"}),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "AuthorizeMW_WithoutScope_ReturnsForbidden",
+			ctx:      jwtbrick.ClaimsToCtx(context.Background(), jwt.MapClaims{"scope": "orders:read"}),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "AuthorizeMW_WithoutClaims_ReturnsUnauthorized",
+			ctx:      context.Background(),
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			rr := httptest.NewRecorder()
+			AuthorizeMW(jwtbrick.RequireScopes("users:read"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
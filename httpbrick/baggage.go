@@ -0,0 +1,39 @@
+package httpbrick
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/otelbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// BaggageMW is a middleware that promotes the W3C baggage members with keys from the allow-list
+// to the context attributes and the request logger (see otelbrick.BaggageToCtx).
+// The context attributes are used in metrics, so allow only the keys with bounded values
+// or the ones overridden by claimsMapping: the client controls the rest of the baggage.
+// The baggage is extracted from the request headers unless it's already in the context (e.g. by OTelTraceMW).
+// If claimsMapping isn't empty, the token claims are set to the baggage first (see jwtbrick.ClaimsToBaggage),
+// replacing the members with the mapped keys sent by the client, so put it after TokenClaimsMW.
+// Put it after SlogCtxMW, so the request logger gets the attributes.
+func BaggageMW(allowList []string, claimsMapping map[string]string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			if baggage.FromContext(ctx).Len() == 0 {
+				ctx = propagation.Baggage{}.Extract(ctx, propagation.HeaderCarrier(req.Header))
+			}
+			if len(claimsMapping) > 0 {
+				var err error
+				if ctx, err = jwtbrick.ClaimsToBaggage(ctx, TokenClaimsFromCtx(ctx), claimsMapping); err != nil {
+					slogbrick.FromCtx(ctx).Warn("failed set baggage from token claims", slog.Any("err", err))
+				}
+			}
+			h.ServeHTTP(w, req.WithContext(otelbrick.BaggageToCtx(ctx, allowList...)))
+		})
+	}
+}
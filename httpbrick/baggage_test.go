@@ -0,0 +1,63 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/otelbrick"
+)
+
+func TestBaggageMW(t *testing.T) {
+	var gotAttrs []attribute.KeyValue
+	h := BaggageMW([]string{"tenant.id", "client.app"}, map[string]string{"tenant_id": "tenant.id"})(
+		http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			gotAttrs = otelbrick.AttrsFromCtx(req.Context())
+		}))
+
+	tests := []struct {
+		name      string
+		baggage   string
+		claims    jwt.MapClaims
+		wantAttrs []attribute.KeyValue
+	}{
+		{
+			name:      "BaggageMW_WithAllowedMembers_PromotesAttrs",
+			baggage:   "client.app=ios,session.id=s1",
+			wantAttrs: []attribute.KeyValue{attribute.String("client.app", "ios")},
+		},
+		{
+			name:      "BaggageMW_WithClaims_OverridesMembers",
+			baggage:   "tenant.id=forged",
+			claims:    jwt.MapClaims{"tenant_id": "acme"},
+			wantAttrs: []attribute.KeyValue{attribute.String("tenant.id", "acme")},
+		},
+		{
+			name:      "BaggageMW_WithoutMappedClaim_DropsMembers",
+			baggage:   "tenant.id=forged,client.app=ios",
+			claims:    jwt.MapClaims{"sub": "user-1"},
+			wantAttrs: []attribute.KeyValue{attribute.String("client.app", "ios")},
+		},
+		{
+			name: "BaggageMW_WithoutBaggage_DoesNothing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.baggage != "" {
+				req.Header.Set("baggage", tt.baggage)
+			}
+			if tt.claims != nil {
+				req = req.WithContext(jwtbrick.ClaimsToCtx(req.Context(), tt.claims))
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.wantAttrs, gotAttrs)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package httpbrick
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/demeero/bricks/breakerbrick"
+)
+
+// BreakerTransport is an http.RoundTripper protected by the circuit breaker.
+type BreakerTransport struct {
+	base    http.RoundTripper
+	breaker *breakerbrick.Breaker
+}
+
+// NewBreakerTransport wraps the base http.RoundTripper (http.DefaultTransport if nil) with the circuit breaker.
+// Transport errors and 5xx responses are recorded as failures.
+// If the circuit is open, the request isn't sent and the error wrapping errbrick.ErrCircuitOpen is returned.
+// Use a breaker per dependency, e.g. a transport per client of the downstream service.
+func NewBreakerTransport(base http.RoundTripper, breaker *breakerbrick.Breaker) *BreakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &BreakerTransport{base: base, breaker: breaker}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := t.breaker.Allow(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil:
+		done(err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		done(fmt.Errorf("http server error: %s", resp.Status))
+	default:
+		done(nil)
+	}
+	return resp, err
+}
@@ -0,0 +1,50 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/breakerbrick"
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestBreakerTransport_RoundTrip(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+		if req.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	breaker, err := breakerbrick.New("users", breakerbrick.WithMinCalls(2))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = breaker.Close() })
+	client := &http.Client{Transport: NewBreakerTransport(nil, breaker)}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(srv.URL + "/missing")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, breakerbrick.StateClosed, breaker.State(), "4xx responses aren't failures")
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, breakerbrick.StateOpen, breaker.State())
+
+	_, err = client.Get(srv.URL)
+	assert.ErrorIs(t, err, errbrick.ErrCircuitOpen)
+	assert.Equal(t, int32(6), calls.Load())
+}
@@ -0,0 +1,32 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+// LivezHandler returns a handler for the liveness probe (e.g. /livez) that responds with healthbrick.Report.
+// It responds with http.StatusServiceUnavailable if the report is down.
+func LivezHandler(reg *healthbrick.Registry) http.Handler {
+	return healthHandler(reg.Live)
+}
+
+// ReadyzHandler returns a handler for the readiness probe (e.g. /readyz) that responds with healthbrick.Report.
+// It responds with http.StatusServiceUnavailable if the report is down, degraded report is considered ready.
+func ReadyzHandler(reg *healthbrick.Registry) http.Handler {
+	return healthHandler(reg.Ready)
+}
+
+func healthHandler(report func(ctx context.Context) healthbrick.Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		res := report(req.Context())
+		status := http.StatusOK
+		if res.Status == healthbrick.StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		JSONResponse(w, status, res)
+	})
+}
@@ -0,0 +1,51 @@
+package httpbrick
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	reg := healthbrick.NewRegistry()
+	reg.Register("db", func(context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		wantCode   int
+		wantStatus healthbrick.Status
+	}{
+		{
+			name:       "LivezHandler_WithFailedReadinessCheck_ReturnsOK",
+			handler:    LivezHandler(reg),
+			wantCode:   http.StatusOK,
+			wantStatus: healthbrick.StatusUp,
+		},
+		{
+			name:       "ReadyzHandler_WithFailedCriticalCheck_ReturnsServiceUnavailable",
+			handler:    ReadyzHandler(reg),
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: healthbrick.StatusDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.wantCode, rr.Code)
+
+			var report healthbrick.Report
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+			assert.Equal(t, tt.wantStatus, report.Status)
+		})
+	}
+}
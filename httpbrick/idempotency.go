@@ -0,0 +1,73 @@
+package httpbrick
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/idempotencybrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// IdempotencyMW is a middleware that replays the stored response for the repeated requests with the same idempotency key.
+// The key is scoped by the JWT subject (see TokenClaimsFromCtx), so put it after TokenClaimsMW.
+// The anonymous requests (without the subject) are handled as if they had no key.
+// The requests with the key reused for another method, URI or body and the concurrent duplicates
+// are rejected with errbrick.ErrConflict written via WriteError.
+// The server errors (5xx) and the panics aren't stored, so such requests can be retried.
+func IdempotencyMW(keeper *idempotencybrick.Keeper, skipper Skipper) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if skipper != nil && skipper(req) {
+				h.ServeHTTP(w, req)
+				return
+			}
+			sub, _ := TokenClaimsFromCtx(req.Context()).GetSubject()
+			key := keeper.Key(req, sub)
+			if key == "" {
+				h.ServeHTTP(w, req)
+				return
+			}
+			fingerprint, err := idempotencybrick.Fingerprint(req)
+			if err != nil {
+				WriteError(w, req, errbrick.NewDetailed(errbrick.ErrInvalidData, err.Error()))
+				return
+			}
+			rec, err := keeper.Begin(req.Context(), key, fingerprint)
+			if err != nil {
+				WriteError(w, req, err)
+				return
+			}
+			lg := slogbrick.FromCtx(req.Context())
+			if rec != nil {
+				if err := idempotencybrick.Replay(w, rec); err != nil {
+					lg.Error("failed replay idempotent resp", slog.Any("err", err))
+				}
+				return
+			}
+			cw, capture := idempotencybrick.NewCapture(w)
+			completed := false
+			defer func() {
+				// the handler panicked (see RecoverMW), so unlock the key for the retries
+				if !completed {
+					unlockIdempotencyKey(req.Context(), keeper, key, fingerprint)
+				}
+			}()
+			h.ServeHTTP(cw, req)
+			completed = true
+			// the response is already sent, so the client disconnect must not prevent storing it
+			if err := keeper.Complete(context.WithoutCancel(req.Context()), key, capture.Record(fingerprint)); err != nil {
+				lg.Error("failed complete idempotent req", slog.Any("err", err))
+			}
+		})
+	}
+}
+
+// unlockIdempotencyKey completes the request with the server error, so the key is unlocked.
+func unlockIdempotencyKey(ctx context.Context, keeper *idempotencybrick.Keeper, key, fingerprint string) {
+	rec := idempotencybrick.Record{Fingerprint: fingerprint, Status: http.StatusInternalServerError}
+	if err := keeper.Complete(context.WithoutCancel(ctx), key, rec); err != nil {
+		slogbrick.FromCtx(ctx).Error("failed unlock idempotency key", slog.Any("err", err))
+	}
+}
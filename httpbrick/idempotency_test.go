@@ -0,0 +1,91 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/idempotencybrick"
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestIdempotencyMW(t *testing.T) {
+	var calls int
+	h := IdempotencyMW(idempotencybrick.NewKeeper(idempotencybrick.NewMemoryStore()), nil)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.Header().Set("Location", "/orders/1")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}))
+
+	tests := []struct {
+		name         string
+		sub          string
+		key          string
+		body         string
+		wantCode     int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{name: "IdempotencyMW_WithNewKey_HandlesReq", sub: "user-1", key: "k1", body: "a", wantCode: http.StatusCreated, wantCalls: 1},
+		{name: "IdempotencyMW_WithRepeatedKey_ReplaysResp", sub: "user-1", key: "k1", body: "a", wantCode: http.StatusCreated, wantCalls: 1, wantReplayed: true},
+		{name: "IdempotencyMW_WithDifferentBody_Conflicts", sub: "user-1", key: "k1", body: "b", wantCode: http.StatusConflict, wantCalls: 1},
+		{name: "IdempotencyMW_WithAnotherSubject_HandlesReq", sub: "user-2", key: "k1", body: "b", wantCode: http.StatusCreated, wantCalls: 2},
+		{name: "IdempotencyMW_WithoutKey_HandlesReq", sub: "user-1", body: "a", wantCode: http.StatusCreated, wantCalls: 3},
+		{name: "IdempotencyMW_WithAnonymousCaller_HandlesReq", key: "k2", body: "a", wantCode: http.StatusCreated, wantCalls: 4},
+		{name: "IdempotencyMW_WithAnotherAnonymousCaller_HandlesReq", key: "k2", body: "a", wantCode: http.StatusCreated, wantCalls: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			req = req.WithContext(jwtbrick.ClaimsToCtx(req.Context(), jwt.MapClaims{"sub": tt.sub}))
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			rr := httptest.NewRecorder()
+			rr.Header().Set("X-Request-ID", tt.name)
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantCode == http.StatusCreated {
+				assert.Equal(t, `{"id":1}`, rr.Body.String())
+				assert.Equal(t, "/orders/1", rr.Header().Get("Location"))
+				assert.Equal(t, tt.name, rr.Header().Get("X-Request-ID"))
+			}
+			if tt.wantReplayed {
+				assert.Equal(t, "true", rr.Header().Get(idempotencybrick.ReplayedHeader))
+			} else {
+				assert.Empty(t, rr.Header().Get(idempotencybrick.ReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotencyMW_WithPanic_UnlocksKey(t *testing.T) {
+	var calls int
+	h := IdempotencyMW(idempotencybrick.NewKeeper(idempotencybrick.NewMemoryStore()), nil)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			if calls == 1 {
+				panic("boom")
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("a"))
+		req = req.WithContext(jwtbrick.ClaimsToCtx(req.Context(), jwt.MapClaims{"sub": "user-1"}))
+		req.Header.Set("Idempotency-Key", "k1")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.PanicsWithValue(t, "boom", func() { serve() })
+	assert.Equal(t, http.StatusCreated, serve().Code)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,29 @@
+package httpbrick
+
+import (
+	"net/http"
+
+	"github.com/demeero/bricks/shedbrick"
+)
+
+// LoadShedMW is a middleware that limits the number of concurrent requests.
+// The requests over the limit wait briefly for a free slot (see shedbrick.WithQueueTimeout)
+// and are rejected with errbrick.ErrUnavailable written via WriteError (503 with Retry-After).
+// Put it after the access log and meter middlewares, so the rejected requests are logged and measured.
+func LoadShedMW(limiter *shedbrick.Limiter, skipper Skipper) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if skipper != nil && skipper(req) {
+				h.ServeHTTP(w, req)
+				return
+			}
+			permit, err := limiter.Acquire(req.Context())
+			if err != nil {
+				WriteError(w, req, err)
+				return
+			}
+			defer permit.Release()
+			h.ServeHTTP(w, req)
+		})
+	}
+}
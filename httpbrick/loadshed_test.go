@@ -0,0 +1,47 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/shedbrick"
+)
+
+func TestLoadShedMW(t *testing.T) {
+	limiter, err := shedbrick.New("test", shedbrick.WithLimit(1), shedbrick.WithQueueTimeout(0))
+	require.NoError(t, err)
+	defer limiter.Close()
+	permit, err := limiter.Acquire(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	require.NoError(t, err)
+	t.Cleanup(permit.Release)
+
+	h := LoadShedMW(limiter, func(req *http.Request) bool {
+		return req.URL.Path == "/health"
+	})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{name: "LoadShedMW_WithExceededLimit_Rejects", path: "/", wantCode: http.StatusServiceUnavailable},
+		{name: "LoadShedMW_WithSkipper_Skips", path: "/health", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusServiceUnavailable {
+				assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+				assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
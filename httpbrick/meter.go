@@ -132,7 +132,7 @@ func OTelMeterMW(options ...OTelMeterMWOption) (func(h http.Handler) http.Handle
 				attrs = append(attrs, semconv.URLPathKey.String(reqURI))
 			}
 			if opts.Attrs.AttrsFromCtx {
-				attrs = append(attrs, otelbrick.AttrsFromCtx(req.Context())...)
+				attrs = append(attrs, otelbrick.MetricAttrsFromCtx(req.Context())...)
 			}
 
 			if opts.Attrs.AttrsToCtx {
@@ -0,0 +1,92 @@
+package httpbrick
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+// Reason, Domain, Metadata, Violations and RetryAfter are extension members filled from errbrick.DetailedError.
+type Problem struct {
+	Metadata   map[string]string         `json:"metadata,omitempty"`
+	Type       string                    `json:"type"`
+	Title      string                    `json:"title"`
+	Detail     string                    `json:"detail,omitempty"`
+	Instance   string                    `json:"instance,omitempty"`
+	TraceID    string                    `json:"trace_id,omitempty"`
+	Reason     string                    `json:"reason,omitempty"`
+	Domain     string                    `json:"domain,omitempty"`
+	Violations []errbrick.FieldViolation `json:"violations,omitempty"`
+	Status     int                       `json:"status"`
+	RetryAfter int64                     `json:"retry_after,omitempty"`
+}
+
+// NewProblem creates Problem from the error.
+// errbrick errors are mapped to HTTP statuses via the errbrick registry (see errbrick.Register).
+// Unknown errors are represented as 500 without any details, so internals are never exposed to the client.
+func NewProblem(req *http.Request, err error) Problem {
+	problem := Problem{
+		Type:     "about:blank",
+		Status:   http.StatusInternalServerError,
+		Instance: req.URL.Path,
+	}
+	if spanCtx := trace.SpanContextFromContext(req.Context()); spanCtx.HasTraceID() {
+		problem.TraceID = spanCtx.TraceID().String()
+	}
+	if mapping, ok := errbrick.Lookup(err); ok {
+		body := errbrick.NewErrorBody(err)
+		problem.Status = mapping.HTTPStatus
+		problem.Detail = body.Message
+		problem.Reason = body.Reason
+		problem.Domain = body.Domain
+		problem.Metadata = body.Metadata
+		problem.Violations = body.Violations
+		problem.RetryAfter = body.RetryAfter
+		// non-standard statuses (e.g. errbrick.StatusClientClosedRequest) don't have a status text
+		problem.Title = mapping.Err.Error()
+	}
+	if title := http.StatusText(problem.Status); title != "" {
+		problem.Title = title
+	}
+	return problem
+}
+
+// WriteError writes the error as application/problem+json response (see NewProblem).
+// Unknown errors are logged via logger from the request context.
+// It must be called before anything is written to the response.
+func WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	problem := NewProblem(req, err)
+	lg := slogbrick.FromCtx(req.Context())
+	if problem.Status == http.StatusInternalServerError {
+		lg.Error("internal server err", slog.Any("err", err))
+	}
+	if problem.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(problem.RetryAfter, 10))
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		lg.Error("failed send err resp", slog.Any("err", err))
+	}
+}
+
+// ErrHandlerFunc is an HTTP handler that returns an error.
+// The returned error is written to the response via WriteError,
+// so the handler must not write anything to the response when it returns an error.
+type ErrHandlerFunc func(w http.ResponseWriter, req *http.Request) error
+
+func (f ErrHandlerFunc) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := f(w, req); err != nil {
+		WriteError(w, req, err)
+	}
+}
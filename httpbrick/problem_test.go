@@ -0,0 +1,88 @@
+package httpbrick
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestErrHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantBody       string
+		wantLog        string
+		wantRetryAfter string
+		wantCode       int
+	}{
+		{
+			name:     "ErrHandlerFunc_WithoutError_WritesHandlerResponse",
+			wantCode: http.StatusOK,
+			wantBody: `{"message":"ok"}`,
+		},
+		{
+			name:     "ErrHandlerFunc_WithNotFoundError_WritesProblem",
+			err:      fmt.Errorf("failed get user: %w", errbrick.ErrNotFound),
+			wantCode: http.StatusNotFound,
+			wantBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed get user: not found","instance":"/users/1","trace_id":"0102030405060708090a0b0c0d0e0f10"}`,
+		},
+		{
+			name: "ErrHandlerFunc_WithDetailedError_WritesProblemWithExtensions",
+			err: errbrick.NewDetailed(errbrick.ErrRateLimited, "slow down",
+				errbrick.WithReason("TOO_FAST"), errbrick.WithRetryAfter(3*time.Second)),
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: "3",
+			wantBody:       `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"rate limited: slow down","instance":"/users/1","trace_id":"0102030405060708090a0b0c0d0e0f10","reason":"TOO_FAST","retry_after":3}`,
+		},
+		{
+			name:     "ErrHandlerFunc_WithUnknownError_WritesInternalProblemAndLogs",
+			err:      errbrick.NewDetailed(nil, "db password is wrong"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/users/1","trace_id":"0102030405060708090a0b0c0d0e0f10"}`,
+			wantLog:  "db password is wrong",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+
+			spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+				SpanID:  trace.SpanID{1},
+			})
+			ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil).WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			ErrHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+				if tt.err != nil {
+					return tt.err
+				}
+				JSONResponseMsg(w, http.StatusOK, "ok")
+				return nil
+			}).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			assert.Equal(t, tt.wantRetryAfter, rr.Header().Get("Retry-After"))
+			if tt.err != nil {
+				assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
+			}
+			if tt.wantLog != "" {
+				assert.Contains(t, buf.String(), tt.wantLog)
+			}
+		})
+	}
+}
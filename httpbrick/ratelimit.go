@@ -0,0 +1,86 @@
+package httpbrick
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/demeero/bricks/ratelimitbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// RateLimitKeyFunc returns the key to limit the request by.
+type RateLimitKeyFunc func(req *http.Request) string
+
+// RateLimitKeyByIP returns the client IP from the request remote address.
+// Put a middleware that resolves the real client IP (e.g. from X-Forwarded-For) before it if the service is behind a proxy.
+func RateLimitKeyByIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return "ip:" + req.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// RateLimitKeyBySubject returns the JWT subject from the token claims (see TokenClaimsFromCtx).
+// It falls back to RateLimitKeyByIP for anonymous requests.
+func RateLimitKeyBySubject(req *http.Request) string {
+	if sub, err := TokenClaimsFromCtx(req.Context()).GetSubject(); err == nil && sub != "" {
+		return "sub:" + sub
+	}
+	return RateLimitKeyByIP(req)
+}
+
+// RateLimitMWOption is a function that configures rate limit middleware.
+type RateLimitMWOption func(*rateLimitMWOpts)
+
+type rateLimitMWOpts struct {
+	Skipper Skipper
+	KeyFunc RateLimitKeyFunc
+}
+
+// WithRateLimitSkipper sets the skipper for the middleware.
+func WithRateLimitSkipper(skipper Skipper) RateLimitMWOption {
+	return func(opts *rateLimitMWOpts) {
+		opts.Skipper = skipper
+	}
+}
+
+// WithRateLimitKeyFunc sets the function to get the key to limit the request by.
+// RateLimitKeyByIP is used by default.
+func WithRateLimitKeyFunc(f RateLimitKeyFunc) RateLimitMWOption {
+	return func(opts *rateLimitMWOpts) {
+		opts.KeyFunc = f
+	}
+}
+
+// RateLimitMW is a middleware that limits the requests rate by the key.
+// It sets RateLimit-* headers (see ratelimitbrick.SetHeaders) and rejects the requests over the limit
+// with errbrick.ErrRateLimited written via WriteError (429 with Retry-After).
+// If the limiter fails (e.g. the store is unavailable), the error is logged and the request is allowed.
+func RateLimitMW(limiter ratelimitbrick.Limiter, options ...RateLimitMWOption) func(http.Handler) http.Handler {
+	opts := rateLimitMWOpts{KeyFunc: RateLimitKeyByIP}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if opts.Skipper != nil && opts.Skipper(req) {
+				h.ServeHTTP(w, req)
+				return
+			}
+			res, err := limiter.Allow(req.Context(), opts.KeyFunc(req))
+			if err != nil {
+				slogbrick.FromCtx(req.Context()).Error("failed check rate limit", slog.Any("err", err))
+				h.ServeHTTP(w, req)
+				return
+			}
+			ratelimitbrick.SetHeaders(w.Header(), res)
+			if !res.Allowed {
+				WriteError(w, req, res.Err())
+				return
+			}
+			h.ServeHTTP(w, req)
+		})
+	}
+}
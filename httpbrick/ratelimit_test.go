@@ -0,0 +1,46 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/ratelimitbrick"
+)
+
+func TestRateLimitMW(t *testing.T) {
+	limiter := ratelimitbrick.NewTokenBucket(ratelimitbrick.NewMemoryStore(), 0.001, 1)
+	h := RateLimitMW(limiter, WithRateLimitSkipper(func(req *http.Request) bool {
+		return req.URL.Path == "/health"
+	}))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		wantCode   int
+	}{
+		{name: "RateLimitMW_WithFirstReq_Allows", path: "/", remoteAddr: "10.0.0.1:1000", wantCode: http.StatusOK},
+		{name: "RateLimitMW_WithExceededLimit_Rejects", path: "/", remoteAddr: "10.0.0.1:2000", wantCode: http.StatusTooManyRequests},
+		{name: "RateLimitMW_WithAnotherIP_Allows", path: "/", remoteAddr: "10.0.0.2:1000", wantCode: http.StatusOK},
+		{name: "RateLimitMW_WithSkipper_Skips", path: "/health", remoteAddr: "10.0.0.1:1000", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusTooManyRequests {
+				assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+				assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+				assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
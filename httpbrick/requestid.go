@@ -0,0 +1,47 @@
+package httpbrick
+
+import (
+	"net/http"
+
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+// RequestIDMW is a middleware that reads the request ID from the X-Request-ID header or generates a new one,
+// binds it to the request context and the logger (see requestidbrick.Bind) and echoes it in the response header.
+// Put it after SlogCtxMW, so the request logger gets the request ID.
+func RequestIDMW(options ...requestidbrick.Option) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx, id := requestidbrick.Bind(req.Context(), req.Header.Get(requestidbrick.Header), options...)
+			w.Header().Set(requestidbrick.Header, id)
+			h.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDTransport is an http.RoundTripper that forwards the request ID from the request context.
+type RequestIDTransport struct {
+	base http.RoundTripper
+}
+
+// NewRequestIDTransport wraps the base http.RoundTripper (http.DefaultTransport if nil)
+// to set the X-Request-ID header from the request context (see requestidbrick.FromCtx).
+// The header already set on the request isn't overwritten.
+func NewRequestIDTransport(base http.RoundTripper) *RequestIDTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RequestIDTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := requestidbrick.FromCtx(req.Context())
+	if id == "" || req.Header.Get(requestidbrick.Header) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTrip must not modify the request, so the header is set to a clone
+	req = req.Clone(req.Context())
+	req.Header.Set(requestidbrick.Header, id)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,64 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+func TestRequestIDMW(t *testing.T) {
+	var gotID string
+	h := RequestIDMW()(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		gotID = requestidbrick.FromCtx(req.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		wantID   string
+	}{
+		{name: "RequestIDMW_WithIncomingID_KeepsID", incoming: "req-42", wantID: "req-42"},
+		{name: "RequestIDMW_WithoutIncomingID_GeneratesID"},
+		{name: "RequestIDMW_WithInvalidID_GeneratesID", incoming: "req 42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(requestidbrick.Header, tt.incoming)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			require.True(t, requestidbrick.Valid(gotID))
+			assert.Equal(t, gotID, rr.Header().Get(requestidbrick.Header))
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, gotID)
+			} else {
+				assert.NotEqual(t, tt.incoming, gotID)
+			}
+		})
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	var gotID string
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		gotID = req.Header.Get(requestidbrick.Header)
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Transport: NewRequestIDTransport(nil)}
+
+	req, err := http.NewRequestWithContext(requestidbrick.ToCtx(context.Background(), "req-42"), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "req-42", gotID)
+	assert.Empty(t, req.Header.Get(requestidbrick.Header))
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
+
+	"github.com/demeero/bricks/errbrick"
 )
 
 func JSONResponse(w http.ResponseWriter, status int, data any) {
@@ -27,3 +30,13 @@ func JSONResponseMsg(w http.ResponseWriter, status int, msg string) {
 		slog.Error("failed to encode JSON response", slog.Any("err", err))
 	}
 }
+
+// JSONErrResponse writes errbrick.ErrorBody created from the error.
+// If the error is errbrick.DetailedError with a retry hint, the Retry-After header is set.
+func JSONErrResponse(w http.ResponseWriter, status int, err error) {
+	body := errbrick.NewErrorBody(err)
+	if body.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(body.RetryAfter, 10))
+	}
+	JSONResponse(w, status, body)
+}
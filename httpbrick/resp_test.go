@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/errbrick"
 )
 
 func TestJSONResponse(t *testing.T) {
@@ -79,3 +82,36 @@ func TestJSONResponseMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONErrResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantRetryAfter string
+		wantBody       string
+	}{
+		{
+			name:     "JSONErrResponse_WithPlainError_ReturnsMessage",
+			err:      errbrick.ErrNotFound,
+			wantBody: `{"message":"not found"}`,
+		},
+		{
+			name: "JSONErrResponse_WithDetailedError_ReturnsDetails",
+			err: errbrick.NewDetailed(errbrick.ErrConflict, "slot is taken",
+				errbrick.WithReason("SLOT_TAKEN"), errbrick.WithMetadata("slot", "1"), errbrick.WithRetryAfter(time.Second)),
+			wantRetryAfter: "1",
+			wantBody:       `{"metadata":{"slot":"1"},"message":"conflict: slot is taken","reason":"SLOT_TAKEN","retry_after":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			JSONErrResponse(rr, http.StatusConflict, tt.err)
+
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Equal(t, tt.wantRetryAfter, rr.Header().Get("Retry-After"))
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package httpbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// RetryOption is a function that configures the retry transport.
+type RetryOption func(*retryOpts)
+
+type retryOpts struct {
+	Retryable         func(resp *http.Response, err error) bool
+	IdempotencyHeader string
+	MetricName        string
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	BudgetRatio       float64
+	BudgetBurst       int
+	MaxAttempts       int
+}
+
+// WithRetryMaxAttempts sets the max number of attempts including the first one.
+// 3 is used by default.
+func WithRetryMaxAttempts(n int) RetryOption {
+	return func(opts *retryOpts) {
+		opts.MaxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets the base and the max delay of the exponential backoff.
+// The max delay also bounds Retry-After: the response asking to wait longer is returned without retries.
+// 100ms and 5s are used by default.
+func WithRetryBackoff(base, maxDelay time.Duration) RetryOption {
+	return func(opts *retryOpts) {
+		opts.BaseDelay = base
+		opts.MaxDelay = maxDelay
+	}
+}
+
+// WithRetryBudget sets the retry budget: each request deposits ratio of a retry to the budget
+// and each retry withdraws one, so retries don't exceed ratio of the requests.
+// The burst is the max number of accumulated retries, the budget starts full.
+// 0.1 ratio and 10 burst are used by default.
+func WithRetryBudget(ratio float64, burst int) RetryOption {
+	return func(opts *retryOpts) {
+		opts.BudgetRatio = ratio
+		opts.BudgetBurst = burst
+	}
+}
+
+// WithRetryIdempotencyHeader sets the header that marks non-idempotent requests (e.g. POST) safe to retry.
+// "Idempotency-Key" is used by default.
+func WithRetryIdempotencyHeader(header string) RetryOption {
+	return func(opts *retryOpts) {
+		opts.IdempotencyHeader = header
+	}
+}
+
+// WithRetryPolicy sets the function that decides if the attempt should be retried (see DefaultRetryPolicy).
+func WithRetryPolicy(f func(resp *http.Response, err error) bool) RetryOption {
+	return func(opts *retryOpts) {
+		opts.Retryable = f
+	}
+}
+
+// WithRetryMetricName sets the name of the retries counter.
+// "http.client.retries" is used by default.
+func WithRetryMetricName(name string) RetryOption {
+	return func(opts *retryOpts) {
+		opts.MetricName = name
+	}
+}
+
+// DefaultRetryPolicy retries transport errors (except the context cancellation)
+// and 429, 502, 503, 504 responses.
+func DefaultRetryPolicy(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryBudget is a token bucket that limits retries to a ratio of the requests.
+type retryBudget struct {
+	tokens float64
+	max    float64
+	ratio  float64
+	mu     sync.Mutex
+}
+
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.max, b.tokens+b.ratio)
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RetryTransport is an http.RoundTripper that retries failed requests.
+type RetryTransport struct {
+	base         http.RoundTripper
+	budget       *retryBudget
+	retryCounter metric.Int64Counter
+	opts         retryOpts
+}
+
+// NewRetryTransport wraps the base http.RoundTripper (http.DefaultTransport if nil) to retry the requests
+// that are idempotent (GET, HEAD, OPTIONS, TRACE, PUT, DELETE) or marked with the idempotency header.
+// The delay between attempts is exponential with full jitter, Retry-After response header overrides it.
+// The retries are stopped if the delay exceeds the request context deadline, Retry-After exceeds the max delay
+// (see WithRetryBackoff) or the retry budget is exhausted.
+// Each retry is recorded as "http.retry" event of the current span and counted by http.client.retries metric.
+// Wrap the instrumented Transport with it to have a client span per attempt.
+func NewRetryTransport(base http.RoundTripper, options ...RetryOption) (*RetryTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	opts := retryOpts{
+		Retryable:         DefaultRetryPolicy,
+		IdempotencyHeader: "Idempotency-Key",
+		MetricName:        "http.client.retries",
+		BaseDelay:         100 * time.Millisecond,
+		MaxDelay:          5 * time.Second,
+		BudgetRatio:       0.1,
+		BudgetBurst:       10,
+		MaxAttempts:       3,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	counter, err := otel.GetMeterProvider().Meter("bricks/httpbrick/retry").
+		Int64Counter(opts.MetricName, metric.WithDescription("The number of HTTP client request retries."))
+	if err != nil {
+		return nil, fmt.Errorf("failed create %s metric: %w", opts.MetricName, err)
+	}
+	return &RetryTransport{
+		base:         base,
+		retryCounter: counter,
+		opts:         opts,
+		budget: &retryBudget{
+			tokens: float64(opts.BudgetBurst),
+			max:    float64(opts.BudgetBurst),
+			ratio:  opts.BudgetRatio,
+		},
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.budget.deposit()
+	if !t.retryable(req) {
+		return t.base.RoundTrip(req)
+	}
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.opts.MaxAttempts || !t.opts.Retryable(resp, err) {
+			return resp, err
+		}
+		delay, ok := t.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if !t.budget.withdraw() {
+			slogbrick.FromCtx(ctx).Debug("http retry budget exhausted", slog.String("http.url", req.URL.Redacted()))
+			return resp, err
+		}
+		t.recordRetry(ctx, req, attempt, delay, resp, err)
+		if resp != nil {
+			drainBody(resp.Body)
+		}
+		if req, err = rewindBody(req); err != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) retryable(req *http.Request) bool {
+	if t.opts.MaxAttempts < 2 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get(t.opts.IdempotencyHeader) != ""
+	}
+}
+
+// delay returns the delay before the next attempt.
+// It returns false if Retry-After asks to wait longer than the max delay.
+func (t *RetryTransport) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, d <= t.opts.MaxDelay
+		}
+	}
+	backoff := t.opts.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		backoff = min(t.opts.MaxDelay, t.opts.BaseDelay<<shift)
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	//nolint:gosec // it's ok to use weak random for jitter
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+func (t *RetryTransport) recordRetry(ctx context.Context, req *http.Request, attempt int, delay time.Duration, resp *http.Response, err error) {
+	reason := "error"
+	if resp != nil {
+		reason = strconv.Itoa(resp.StatusCode)
+	}
+	trace.SpanFromContext(ctx).AddEvent("http.retry", trace.WithAttributes(
+		attribute.Int("http.retry.attempt", attempt),
+		attribute.Int64("http.retry.delay_ms", delay.Milliseconds()),
+		attribute.String("http.retry.reason", reason),
+	))
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(req.Method)}
+	if host, _, splitErr := net.SplitHostPort(req.URL.Host); splitErr == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	} else {
+		attrs = append(attrs, semconv.ServerAddress(req.URL.Host))
+	}
+	t.retryCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	slogbrick.FromCtx(ctx).Debug("retrying http req",
+		slog.String("http.url", req.URL.Redacted()),
+		slog.Int("attempt", attempt),
+		slog.Int64("delay_ms", delay.Milliseconds()),
+		slog.String("reason", reason),
+		slog.Any("err", err))
+}
+
+// retryAfter parses Retry-After header value in seconds or HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		return max(0, time.Until(date)), true
+	}
+	return 0, false
+}
+
+func rewindBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed rewind request body: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// drainBody reads the rest of the body (limited) to reuse the connection and closes it.
+func drainBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4096))
+	_ = body.Close()
+}
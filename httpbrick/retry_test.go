@@ -0,0 +1,143 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		header       http.Header
+		failures     int32
+		retryAfter   string
+		options      []RetryOption
+		ctxTimeout   time.Duration
+		wantCode     int
+		wantAttempts int32
+	}{
+		{
+			name:         "RoundTrip_WithTransientFailures_Retries",
+			method:       http.MethodGet,
+			failures:     2,
+			wantCode:     http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "RoundTrip_WithMaxAttemptsExceeded_ReturnsLastResp",
+			method:       http.MethodGet,
+			failures:     5,
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "RoundTrip_WithPost_DoesNotRetry",
+			method:       http.MethodPost,
+			failures:     1,
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "RoundTrip_WithPostAndIdempotencyKey_Retries",
+			method:       http.MethodPost,
+			header:       http.Header{"Idempotency-Key": []string{"key-1"}},
+			failures:     1,
+			wantCode:     http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "RoundTrip_WithExhaustedBudget_DoesNotRetry",
+			method:       http.MethodGet,
+			failures:     1,
+			options:      []RetryOption{WithRetryBudget(0.1, 0)},
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "RoundTrip_WithRetryAfterBeyondDeadline_DoesNotRetry",
+			method:       http.MethodGet,
+			failures:     1,
+			retryAfter:   "10",
+			options:      []RetryOption{WithRetryBackoff(time.Millisecond, time.Minute)},
+			ctxTimeout:   time.Second,
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "RoundTrip_WithRetryAfterBeyondMaxDelay_DoesNotRetry",
+			method:       http.MethodGet,
+			failures:     1,
+			retryAfter:   "86400",
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "RoundTrip_WithRetryAfterWithinMaxDelay_Retries",
+			method:       http.MethodGet,
+			failures:     1,
+			retryAfter:   "0",
+			wantCode:     http.StatusOK,
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if attempts.Add(1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			options := append([]RetryOption{WithRetryBackoff(time.Millisecond, 5*time.Millisecond)}, tt.options...)
+			transport, err := NewRetryTransport(nil, options...)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, tt.method, srv.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, d, float64(2*time.Second))
+
+	_, ok = retryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,222 @@
+package httpbrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/demeero/bricks/configbrick"
+	"github.com/demeero/bricks/requestidbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// ServerOption is a function that configures the server.
+type ServerOption func(*serverOpts)
+
+type serverOpts struct {
+	listener     net.Listener
+	mws          []func(http.Handler) http.Handler
+	logCtxOpts   []LogCtxMWOption
+	accessOpts   []AccessLogMWOption
+	meterOpts    []OTelMeterMWOption
+	recoverOpts  []RecoverMWOption
+	traceOpts    []OTelTraceMWOption
+	reqIDOpts    []requestidbrick.Option
+	withoutMeter bool
+	withoutTrace bool
+	withoutReqID bool
+}
+
+// WithServerListener sets the listener to serve on instead of listening on configbrick.HTTP.Port.
+func WithServerListener(ln net.Listener) ServerOption {
+	return func(opts *serverOpts) {
+		opts.listener = ln
+	}
+}
+
+// WithServerMW adds the middlewares to the end of the standard chain (right before the handler).
+func WithServerMW(mws ...func(http.Handler) http.Handler) ServerOption {
+	return func(opts *serverOpts) {
+		opts.mws = append(opts.mws, mws...)
+	}
+}
+
+// WithServerLogCtxOpts sets the options for SlogCtxMW.
+func WithServerLogCtxOpts(options ...LogCtxMWOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.logCtxOpts = append(opts.logCtxOpts, options...)
+	}
+}
+
+// WithServerAccessLogOpts sets the options for SlogAccessLogMW.
+func WithServerAccessLogOpts(options ...AccessLogMWOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.accessOpts = append(opts.accessOpts, options...)
+	}
+}
+
+// WithServerMeterOpts sets the options for OTelMeterMW.
+func WithServerMeterOpts(options ...OTelMeterMWOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.meterOpts = append(opts.meterOpts, options...)
+	}
+}
+
+// WithServerRecoverOpts sets the options for RecoverMW.
+func WithServerRecoverOpts(options ...RecoverMWOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.recoverOpts = append(opts.recoverOpts, options...)
+	}
+}
+
+// WithServerTraceOpts sets the options for OTelTraceMW.
+func WithServerTraceOpts(options ...OTelTraceMWOption) ServerOption {
+	return func(opts *serverOpts) {
+		opts.traceOpts = append(opts.traceOpts, options...)
+	}
+}
+
+// WithoutServerTrace disables OTelTraceMW.
+func WithoutServerTrace() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutTrace = true
+	}
+}
+
+// WithServerRequestIDOpts sets the options for RequestIDMW.
+func WithServerRequestIDOpts(options ...requestidbrick.Option) ServerOption {
+	return func(opts *serverOpts) {
+		opts.reqIDOpts = append(opts.reqIDOpts, options...)
+	}
+}
+
+// WithoutServerRequestID disables RequestIDMW.
+func WithoutServerRequestID() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutReqID = true
+	}
+}
+
+// WithoutServerMeter disables OTelMeterMW.
+func WithoutServerMeter() ServerOption {
+	return func(opts *serverOpts) {
+		opts.withoutMeter = true
+	}
+}
+
+// Server is an HTTP server built from configbrick.HTTP with the standard middleware chain.
+type Server struct {
+	srv      *http.Server
+	listener net.Listener
+	cfg      configbrick.HTTP
+}
+
+// NewServer creates a new HTTP server.
+// The handler is wrapped with the middlewares in the following order (from the outermost):
+// OTelTraceMW, SlogCtxMW (with span context attributes), RequestIDMW, SlogAccessLogMW (enabled by cfg.AccessLog with cfg.AccessLogLevel), OTelMeterMW, RecoverMW.
+// So the panics are recovered before being counted in metrics and access log,
+// and all middlewares have the logger correlated with the server span in context.
+func NewServer(cfg configbrick.HTTP, h http.Handler, options ...ServerOption) (*Server, error) {
+	opts := serverOpts{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	for i := len(opts.mws) - 1; i >= 0; i-- {
+		h = opts.mws[i](h)
+	}
+	h = RecoverMW(opts.recoverOpts...)(h)
+	if !opts.withoutMeter {
+		meterMW, err := OTelMeterMW(opts.meterOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed create otel meter middleware: %w", err)
+		}
+		h = meterMW(h)
+	}
+	lvl := slogbrick.ParseLevel(cfg.AccessLogLevel, slog.LevelDebug)
+	h = SlogAccessLogMW(cfg.AccessLog, lvl, opts.accessOpts...)(h)
+	if !opts.withoutReqID {
+		h = RequestIDMW(opts.reqIDOpts...)(h)
+	}
+	if opts.withoutTrace {
+		h = SlogCtxMW(opts.logCtxOpts...)(h)
+	} else {
+		h = SlogCtxMW(append([]LogCtxMWOption{WithOTelSpanCtxLogAttr()}, opts.logCtxOpts...)...)(h)
+		h = OTelTraceMW(opts.traceOpts...)(h)
+	}
+
+	return &Server{
+		cfg:      cfg,
+		listener: opts.listener,
+		srv: &http.Server{
+			Addr:              ":" + strconv.Itoa(cfg.Port),
+			Handler:           h,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+		},
+	}, nil
+}
+
+// HTTPServer returns the underlying http.Server, e.g. to tune it before Run.
+func (s *Server) HTTPServer() *http.Server {
+	return s.srv
+}
+
+// Run starts the server and blocks until the context is done or SIGINT/SIGTERM is received.
+// Then it gracefully shuts down the server within configbrick.HTTP.ShutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down http server", slog.Duration("timeout", s.cfg.ShutdownTimeout))
+	return s.Shutdown(context.Background())
+}
+
+// Serve listens on configbrick.HTTP.Port (or the listener set via WithServerListener) and serves the requests.
+// It blocks until the server is shut down, in that case nil is returned.
+func (s *Server) Serve() error {
+	ln := s.listener
+	if ln == nil {
+		var err error
+		if ln, err = net.Listen("tcp", s.srv.Addr); err != nil {
+			return fmt.Errorf("failed listen %s: %w", s.srv.Addr, err)
+		}
+	}
+	slog.Info("starting http server", slog.String("addr", ln.Addr().String()))
+	if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed serve http: %w", err)
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the server within configbrick.HTTP.ShutdownTimeout.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed shutdown http server: %w", err)
+	}
+	slog.Info("http server stopped")
+	return nil
+}
@@ -0,0 +1,59 @@
+package httpbrick
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/configbrick"
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+func TestServer_Run(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	srv, err := NewServer(configbrick.HTTP{
+		AccessLog:         true,
+		AccessLogLevel:    "info",
+		ReadHeaderTimeout: time.Second,
+		ShutdownTimeout:   time.Second,
+	}, mux, WithServerListener(ln), WithServerRecoverOpts(WithRecoverLogStackField(false)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	base := "http://" + ln.Addr().String()
+	resp, err := http.Get(base + "/ok")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.True(t, requestidbrick.Valid(resp.Header.Get(requestidbrick.Header)))
+
+	resp, err = http.Get(base + "/panic")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
@@ -9,13 +9,18 @@ import (
 
 	"github.com/MicahParks/keyfunc/v2"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/demeero/bricks/jwtbrick"
+	"github.com/demeero/bricks/slogbrick"
 )
 
 type TokenClaimsMWOpts func(*tokenClaimsMWOpts)
 
 type tokenClaimsMWOpts struct {
 	RespWriter    func(w http.ResponseWriter, status int, msg string)
+	Validator     *jwtbrick.Validator
 	KeyFuncOpts   keyfunc.Options
+	ValidatorOpts []jwtbrick.ValidatorOption
 	JWKSURL       string
 	Header        string
 	HeaderPrefix  string
@@ -68,6 +73,22 @@ func WithTokenClaimsKeyFuncOpts(opts keyfunc.Options) TokenClaimsMWOpts {
 	}
 }
 
+// WithTokenClaimsValidator sets the validator to verify tokens with.
+// If set, other validation options (JWKS URL, keyfunc and validator options) are ignored.
+func WithTokenClaimsValidator(v *jwtbrick.Validator) TokenClaimsMWOpts {
+	return func(o *tokenClaimsMWOpts) {
+		o.Validator = v
+	}
+}
+
+// WithTokenClaimsValidatorOpts sets the options to create the validator with,
+// e.g. static keys, HMAC secret, issuer, audience etc.
+func WithTokenClaimsValidatorOpts(opts ...jwtbrick.ValidatorOption) TokenClaimsMWOpts {
+	return func(o *tokenClaimsMWOpts) {
+		o.ValidatorOpts = append(o.ValidatorOpts, opts...)
+	}
+}
+
 var defaultTokenClaimsMWOpts = tokenClaimsMWOpts{
 	RespWriter:    JSONResponseMsg,
 	ErrStatusCode: http.StatusUnauthorized,
@@ -75,38 +96,32 @@ var defaultTokenClaimsMWOpts = tokenClaimsMWOpts{
 	HeaderPrefix:  "bearer",
 }
 
-// TokenClaimsMW is a middleware that extracts the JWT token from the request and puts the claims to the request context.
+// TokenClaimsMW is a middleware that extracts the JWT token from the request, validates it and puts the claims to the request context.
+// If the validator can't be created (e.g. no keys configured or JWKS can't be loaded), the error is logged
+// and the middleware rejects all requests - unsigned or unverified tokens are never accepted.
 func TokenClaimsMW(options ...TokenClaimsMWOpts) func(http.Handler) http.Handler {
 	opts := defaultTokenClaimsMWOpts
 	for _, opt := range options {
 		opt(&opts)
 	}
-	var jwtKeyFunc jwt.Keyfunc = func(token *jwt.Token) (interface{}, error) {
-		return jwt.UnsafeAllowNoneSignatureType, nil
-	}
-	if opts.JWKSURL != "" {
-		jwks, err := keyfunc.Get(opts.JWKSURL, opts.KeyFuncOpts)
-		if err != nil {
-			slog.Error("failed create JWKS from resource at the given URL",
-				slog.Any("err", err), slog.String("jwks_url", opts.JWKSURL))
-		} else {
-			jwtKeyFunc = jwks.Keyfunc
-		}
+	validator, validatorErr := tokenClaimsValidator(opts)
+	if validatorErr != nil {
+		slog.Error("failed create JWT validator - all requests will be rejected", slog.Any("err", validatorErr))
 	}
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if validatorErr != nil {
+				opts.RespWriter(w, opts.ErrStatusCode, "token validation is unavailable")
+				return
+			}
 			jwtToken, err := retrieveJWT(opts, req)
 			if err != nil {
 				opts.RespWriter(w, opts.ErrStatusCode, err.Error())
 				return
 			}
-			claims := jwt.MapClaims{}
-			tkn, err := jwt.ParseWithClaims(jwtToken, &claims, jwtKeyFunc)
+			claims, err := validator.Validate(jwtToken)
 			if err != nil {
-				opts.RespWriter(w, opts.ErrStatusCode, "failed parse token")
-				return
-			}
-			if !tkn.Valid {
+				slogbrick.FromCtx(req.Context()).Debug("invalid token", slog.Any("err", err))
 				opts.RespWriter(w, opts.ErrStatusCode, "invalid token")
 				return
 			}
@@ -116,21 +131,25 @@ func TokenClaimsMW(options ...TokenClaimsMWOpts) func(http.Handler) http.Handler
 	}
 }
 
-type jwtTokenClaimsKey struct{}
-
-var tknClaimsKey = jwtTokenClaimsKey{}
+func tokenClaimsValidator(opts tokenClaimsMWOpts) (*jwtbrick.Validator, error) {
+	if opts.Validator != nil {
+		return opts.Validator, nil
+	}
+	validatorOpts := opts.ValidatorOpts
+	if opts.JWKSURL != "" {
+		validatorOpts = append(validatorOpts, jwtbrick.WithJWKSURL(opts.JWKSURL), jwtbrick.WithKeyFuncOpts(opts.KeyFuncOpts))
+	}
+	return jwtbrick.NewValidator(validatorOpts...)
+}
 
 func tokenClaimsToCtx(ctx context.Context, claims jwt.MapClaims) context.Context {
-	return context.WithValue(ctx, tknClaimsKey, claims)
+	return jwtbrick.ClaimsToCtx(ctx, claims)
 }
 
 // TokenClaimsFromCtx returns the token claims from the context.
+// It's a shortcut for jwtbrick.ClaimsFromCtx, use jwtbrick.TypedClaimsFromCtx to get typed claims.
 func TokenClaimsFromCtx(ctx context.Context) jwt.MapClaims {
-	claims, ok := ctx.Value(tknClaimsKey).(jwt.MapClaims)
-	if !ok {
-		return jwt.MapClaims{}
-	}
-	return claims
+	return jwtbrick.ClaimsFromCtx(ctx)
 }
 
 // retrieveJWT returns the token string from the request.
@@ -0,0 +1,83 @@
+package httpbrick
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/jwtbrick"
+)
+
+func TestTokenClaimsMW(t *testing.T) {
+	secret := []byte("secret")
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	require.NoError(t, err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		header   string
+		options  []TokenClaimsMWOpts
+		wantCode int
+	}{
+		{
+			name:     "TokenClaimsMW_WithValidToken_PutsClaimsToCtx",
+			header:   "bearer " + signed,
+			options:  []TokenClaimsMWOpts{WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret))},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "TokenClaimsMW_WithUnsignedToken_Rejects",
+			header:   "bearer " + unsigned,
+			options:  []TokenClaimsMWOpts{WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret))},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "TokenClaimsMW_WithoutHeader_Rejects",
+			options:  []TokenClaimsMWOpts{WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret))},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "TokenClaimsMW_WithoutKeys_FailsClosed",
+			header:   "bearer " + unsigned,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "TokenClaimsMW_WithCustomErrStatusCode_UsesIt",
+			header: "bearer " + unsigned,
+			options: []TokenClaimsMWOpts{
+				WithTokenClaimsValidatorOpts(jwtbrick.WithHMACSecret(secret)),
+				WithTokenClaimsErrStatusCode(http.StatusForbidden),
+			},
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			TokenClaimsMW(tt.options...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "user-1", TokenClaimsFromCtx(r.Context())["sub"])
+			})).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
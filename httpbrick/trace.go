@@ -0,0 +1,181 @@
+package httpbrick
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/felixge/httpsnoop"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+const tracerName = "bricks/httpbrick"
+
+// OTelTraceMWOption is a function that configures tracing middleware.
+type OTelTraceMWOption func(*otelTraceOpts)
+
+type otelTraceOpts struct {
+	Skipper           Skipper
+	Propagator        propagation.TextMapPropagator
+	TracerProvider    trace.TracerProvider
+	SpanNameFormatter func(req *http.Request, route string) string
+	DebugHeader       string
+}
+
+// WithTraceSkipper sets the skipper for the tracing middleware.
+func WithTraceSkipper(skipper Skipper) OTelTraceMWOption {
+	return func(opts *otelTraceOpts) {
+		opts.Skipper = skipper
+	}
+}
+
+// WithTracePropagator sets the propagator to extract the incoming context with.
+// The global propagator (otel.GetTextMapPropagator) is used by default.
+func WithTracePropagator(p propagation.TextMapPropagator) OTelTraceMWOption {
+	return func(opts *otelTraceOpts) {
+		opts.Propagator = p
+	}
+}
+
+// WithTracerProvider sets the tracer provider to start spans with.
+// The global tracer provider (otel.GetTracerProvider) is used by default.
+func WithTracerProvider(tp trace.TracerProvider) OTelTraceMWOption {
+	return func(opts *otelTraceOpts) {
+		opts.TracerProvider = tp
+	}
+}
+
+// WithTraceSpanNameFormatter sets the function to build the span name.
+// "{method} {route}" is used by default, or just "{method}" if the route is unknown.
+func WithTraceSpanNameFormatter(f func(req *http.Request, route string) string) OTelTraceMWOption {
+	return func(opts *otelTraceOpts) {
+		opts.SpanNameFormatter = f
+	}
+}
+
+// WithTraceDebugHeader forces sampling of the requests with the non-empty header (e.g. X-Debug-Trace)
+// via otelbrick.ForceSampleToCtx, so it takes effect with the sampler built by otelbrick.InitTrace (see otelbrick.DebugSampler).
+// The header lets any client force sampling, so strip it from the external requests at the edge.
+func WithTraceDebugHeader(header string) OTelTraceMWOption {
+	return func(opts *otelTraceOpts) {
+		opts.DebugHeader = header
+	}
+}
+
+// ServerTracer starts and ends server spans for incoming HTTP requests.
+// It's a building block for the tracing middlewares of different routers, see OTelTraceMW.
+type ServerTracer struct {
+	opts otelTraceOpts
+}
+
+// NewServerTracer creates a new ServerTracer.
+func NewServerTracer(options ...OTelTraceMWOption) *ServerTracer {
+	opts := otelTraceOpts{
+		SpanNameFormatter: func(req *http.Request, route string) string {
+			if route == "" {
+				return req.Method
+			}
+			return req.Method + " " + route
+		},
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &ServerTracer{opts: opts}
+}
+
+// Start extracts the incoming context and starts a server span with semconv HTTP attributes.
+// The route (e.g. "/users/:id") is used for the span name and http.route attribute, it can be empty.
+// It returns false if the request is skipped, in that case the request is returned as is and the span is noop.
+func (t *ServerTracer) Start(req *http.Request, route string) (*http.Request, trace.Span, bool) {
+	if t.opts.Skipper != nil && t.opts.Skipper(req) {
+		return req, trace.SpanFromContext(req.Context()), false
+	}
+	propagator := t.opts.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	tp := t.opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	if t.opts.DebugHeader != "" && req.Header.Get(t.opts.DebugHeader) != "" {
+		ctx = otelbrick.ForceSampleToCtx(ctx)
+	}
+	ctx, span := tp.Tracer(tracerName).Start(ctx, t.opts.SpanNameFormatter(req, route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(serverSpanAttrs(req, route)...))
+	return req.WithContext(ctx), span, true
+}
+
+// End records the response status code and the error (if any) and ends the span.
+// The span status is set to error for 5xx codes only, 4xx codes are client errors and don't fail the server span.
+func (t *ServerTracer) End(span trace.Span, code int, err error) {
+	if code > 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(code))
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	if code >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(code))
+	}
+	span.End()
+}
+
+// OTelTraceMW is a middleware that starts a server span for each request (see ServerTracer).
+// Put it before SlogCtxMW (with WithOTelSpanCtxLogAttr), so the logger is correlated with the span.
+func OTelTraceMW(options ...OTelTraceMWOption) func(http.Handler) http.Handler {
+	tracer := NewServerTracer(options...)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req, span, ok := tracer.Start(req, "")
+			if !ok {
+				h.ServeHTTP(w, req)
+				return
+			}
+			m := httpsnoop.CaptureMetrics(h, w, req)
+			tracer.End(span, m.Code, nil)
+		})
+	}
+}
+
+func serverSpanAttrs(req *http.Request, route string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 9)
+	attrs = append(attrs,
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(req.URL.Path),
+		semconv.UserAgentOriginal(req.UserAgent()),
+	)
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	attrs = append(attrs, semconv.URLScheme(scheme))
+	if host, port, err := net.SplitHostPort(req.Host); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(p))
+		}
+	} else if req.Host != "" {
+		attrs = append(attrs, semconv.ServerAddress(req.Host))
+	}
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		attrs = append(attrs, semconv.ClientAddress(host))
+	}
+	if req.ProtoMajor > 0 {
+		attrs = append(attrs, semconv.NetworkProtocolVersion(strconv.Itoa(req.ProtoMajor)+"."+strconv.Itoa(req.ProtoMinor)))
+	}
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+	return attrs
+}
@@ -0,0 +1,166 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+func TestOTelTraceMW(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name       string
+		path       string
+		code       int
+		wantStatus codes.Code
+		wantSpans  int
+	}{
+		{
+			name:       "OTelTraceMW_WithTraceparent_ContinuesTrace",
+			path:       "/users",
+			code:       http.StatusOK,
+			wantStatus: codes.Unset,
+			wantSpans:  1,
+		},
+		{
+			name:       "OTelTraceMW_WithServerErr_SetsErrStatus",
+			path:       "/users",
+			code:       http.StatusInternalServerError,
+			wantStatus: codes.Error,
+			wantSpans:  1,
+		},
+		{
+			name:       "OTelTraceMW_WithClientErr_KeepsStatusUnset",
+			path:       "/users",
+			code:       http.StatusNotFound,
+			wantStatus: codes.Unset,
+			wantSpans:  1,
+		},
+		{
+			name:      "OTelTraceMW_WithSkipper_SkipsSpan",
+			path:      "/health",
+			code:      http.StatusOK,
+			wantSpans: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			mw := OTelTraceMW(
+				WithTracerProvider(tp),
+				WithTracePropagator(propagation.TraceContext{}),
+				WithTraceSkipper(func(req *http.Request) bool { return req.URL.Path == "/health" }),
+			)
+			var handlerTraceID trace.TraceID
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				handlerTraceID = trace.SpanContextFromContext(req.Context()).TraceID()
+				w.WriteHeader(tt.code)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("traceparent", traceparent)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := sr.Ended()
+			require.Len(t, spans, tt.wantSpans)
+			if tt.wantSpans == 0 {
+				return
+			}
+			span := spans[0]
+			assert.Equal(t, "GET", span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+			assert.Equal(t, span.SpanContext().TraceID(), handlerTraceID)
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+			assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(tt.code))
+			assert.Contains(t, span.Attributes(), attribute.String("url.path", tt.path))
+		})
+	}
+}
+
+func TestOTelTraceMW_Propagators(t *testing.T) {
+	tests := []struct {
+		name        string
+		propagators []string
+		wantHeader  string
+		wantBaggage bool
+	}{
+		{name: "OTelTraceMW_WithTraceContextAndBaggage_PropagatesBoth", wantHeader: "traceparent", wantBaggage: true},
+		{name: "OTelTraceMW_WithB3_PropagatesTrace", propagators: []string{otelbrick.PropagatorB3}, wantHeader: "b3"},
+		{name: "OTelTraceMW_WithB3Multi_PropagatesTrace", propagators: []string{otelbrick.PropagatorB3Multi}, wantHeader: "X-B3-Traceid"},
+		{name: "OTelTraceMW_WithJaeger_PropagatesTrace", propagators: []string{otelbrick.PropagatorJaeger}, wantHeader: "Uber-Trace-Id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := otelbrick.NewPropagator(tt.propagators...)
+			require.NoError(t, err)
+			tp := sdktrace.NewTracerProvider()
+
+			var (
+				gotHeader  string
+				gotTraceID trace.TraceID
+				gotBaggage baggage.Baggage
+			)
+			srv := httptest.NewServer(OTelTraceMW(WithTracePropagator(p), WithTracerProvider(tp))(
+				http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+					gotHeader = req.Header.Get(tt.wantHeader)
+					gotTraceID = trace.SpanContextFromContext(req.Context()).TraceID()
+					gotBaggage = baggage.FromContext(req.Context())
+				})))
+			t.Cleanup(srv.Close)
+			transport, err := NewTransport(nil, WithTransportPropagator(p), WithTransportTracerProvider(tp))
+			require.NoError(t, err)
+
+			ctx, span := tp.Tracer("test").Start(context.Background(), "parent")
+			defer span.End()
+			ctx, err = otelbrick.SetBaggage(ctx, map[string]string{"tenant.id": "acme"})
+			require.NoError(t, err)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.NotEmpty(t, gotHeader)
+			assert.Equal(t, span.SpanContext().TraceID(), gotTraceID)
+			if tt.wantBaggage {
+				assert.Equal(t, "acme", gotBaggage.Member("tenant.id").Value())
+			}
+		})
+	}
+}
+
+func TestOTelTraceMW_DebugHeader(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(otelbrick.DebugSampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(sr))
+	h := OTelTraceMW(WithTracerProvider(tp), WithTraceDebugHeader("X-Debug-Trace"))(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, sr.Ended())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Debug-Trace", "1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Len(t, sr.Ended(), 1)
+}
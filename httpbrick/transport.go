@@ -0,0 +1,215 @@
+package httpbrick
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/otelbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+type transportMetrics struct {
+	reqDurationHist metric.Int64Histogram
+	reqSizeHist     metric.Int64Histogram
+	respSizeHist    metric.Int64Histogram
+}
+
+func newTransportMetrics(opts transportOpts) (*transportMetrics, error) {
+	meter := otel.GetMeterProvider().Meter("bricks/httpbrick/transport")
+	result := &transportMetrics{}
+	var err error
+	if opts.ReqDuration {
+		result.reqDurationHist, err = meter.Int64Histogram(opts.Names.ReqDurationHist,
+			metric.WithDescription("Duration of HTTP client requests."), metric.WithUnit("ms"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.ReqDurationHist, err)
+		}
+	}
+	if opts.ReqSize {
+		result.reqSizeHist, err = meter.Int64Histogram(opts.Names.ReqSizeHist,
+			metric.WithDescription("Size of HTTP client request bodies."), metric.WithUnit("By"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.ReqSizeHist, err)
+		}
+	}
+	if opts.RespSize {
+		result.respSizeHist, err = meter.Int64Histogram(opts.Names.RespSizeHist,
+			metric.WithDescription("Size of HTTP client response bodies."), metric.WithUnit("By"))
+		if err != nil {
+			return nil, fmt.Errorf("failed create %s metric: %w", opts.Names.RespSizeHist, err)
+		}
+	}
+	return result, nil
+}
+
+// Transport is an instrumented http.RoundTripper for outgoing requests.
+type Transport struct {
+	base    http.RoundTripper
+	metrics *transportMetrics
+	opts    transportOpts
+}
+
+// NewTransport wraps the base http.RoundTripper (http.DefaultTransport if nil) to:
+//   - inject the trace context to the request headers and start a client span;
+//   - record http.client.request.duration, http.client.request.body.size and http.client.response.body.size metrics;
+//   - log the request and the response via slogbrick.FromCtx.
+//
+// The span and the duration cover the time until the response headers are received.
+// The response body size is recorded only if it's known (Content-Length).
+func NewTransport(base http.RoundTripper, options ...TransportOption) (*Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	opts := transportOpts{
+		Names: TransportMetricNames{
+			ReqDurationHist: "http.client.request.duration",
+			ReqSizeHist:     "http.client.request.body.size",
+			RespSizeHist:    "http.client.response.body.size",
+		},
+		LogLevel:     slog.LevelDebug,
+		Trace:        true,
+		Log:          true,
+		ReqDuration:  true,
+		ReqSize:      true,
+		RespSize:     true,
+		AttrsFromCtx: true,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	metrics, err := newTransportMetrics(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed create http client metrics: %w", err)
+	}
+	return &Transport{base: base, metrics: metrics, opts: opts}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.opts.Skipper != nil && t.opts.Skipper(req) {
+		return t.base.RoundTrip(req)
+	}
+	ctx := req.Context()
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(req.Method)}
+	if host, port, err := net.SplitHostPort(req.URL.Host); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(p))
+		}
+	} else {
+		attrs = append(attrs, semconv.ServerAddress(req.URL.Host))
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if t.opts.Trace {
+		tp := t.opts.TracerProvider
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		ctx, span = tp.Tracer(tracerName).Start(ctx, req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(append(attrs, semconv.URLFull(req.URL.Redacted()))...))
+		defer span.End()
+		propagator := t.opts.Propagator
+		if propagator == nil {
+			propagator = otel.GetTextMapPropagator()
+		}
+		// RoundTrip must not modify the request, so the headers are injected to a clone
+		req = req.Clone(ctx)
+		propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	logger := slogbrick.FromCtx(ctx)
+	if t.opts.Log {
+		logger.Log(ctx, t.opts.LogLevel, "outgoing http req",
+			slog.String("http.method", req.Method), slog.String("http.url", req.URL.Redacted()))
+	}
+
+	startTime := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(startTime)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+		if t.opts.CodeAttr {
+			attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+		}
+	}
+
+	if t.opts.Log {
+		logAttrs := []slog.Attr{
+			slog.String("http.method", req.Method),
+			slog.String("http.url", req.URL.Redacted()),
+			slog.Int64("http.duration_ms", duration.Milliseconds()),
+		}
+		if err != nil {
+			logAttrs = append(logAttrs, slog.Any("err", err))
+		} else {
+			logAttrs = append(logAttrs, slog.Int("http.code", resp.StatusCode))
+		}
+		logger.LogAttrs(ctx, t.opts.LogLevel, "incoming http resp", logAttrs...)
+	}
+
+	t.recordMetrics(ctx, req, resp, duration, attrs)
+	return resp, err
+}
+
+func (t *Transport) recordMetrics(ctx context.Context, req *http.Request, resp *http.Response, duration time.Duration, attrs []attribute.KeyValue) {
+	if t.opts.AttrsFromCtx {
+		// the context attributes are shared by the concurrent calls, so they must not be appended in place
+		attrs = append(slices.Clone(otelbrick.MetricAttrsFromCtx(ctx)), attrs...)
+	}
+	attrsOpt := metric.WithAttributes(attrs...)
+	if t.opts.ReqDuration {
+		t.metrics.reqDurationHist.Record(ctx, duration.Milliseconds(), attrsOpt)
+	}
+	if t.opts.ReqSize && req.ContentLength >= 0 {
+		t.metrics.reqSizeHist.Record(ctx, req.ContentLength, attrsOpt)
+	}
+	if t.opts.RespSize && resp != nil && resp.ContentLength >= 0 {
+		t.metrics.respSizeHist.Record(ctx, resp.ContentLength, attrsOpt)
+	}
+}
+
+// ErrFromResponse maps 4xx/5xx responses to errbrick errors via the errbrick registry (see errbrick.LookupHTTPStatus).
+// It returns nil for other responses. The Retry-After header (in seconds) is kept as errbrick.DetailedError retry hint.
+// Unknown statuses are returned as errors that don't wrap errbrick errors.
+// The response body isn't read.
+func ErrFromResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	msg := resp.Status
+	if resp.Request != nil {
+		msg = fmt.Sprintf("%s %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+	}
+	mapping, ok := errbrick.LookupHTTPStatus(resp.StatusCode)
+	if !ok {
+		return fmt.Errorf("unexpected http response: %s", msg)
+	}
+	if secs, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil && secs > 0 {
+		return errbrick.NewDetailed(mapping.Err, msg, errbrick.WithRetryAfter(time.Duration(secs)*time.Second))
+	}
+	return fmt.Errorf("%w: %s", mapping.Err, msg)
+}
@@ -0,0 +1,124 @@
+package httpbrick
+
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TransportMetricNames is a set of metric names for the client transport. Use it to configure the default names.
+type TransportMetricNames struct {
+	ReqDurationHist string
+	ReqSizeHist     string
+	RespSizeHist    string
+}
+
+type transportOpts struct {
+	Skipper        Skipper
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+	Names          TransportMetricNames
+	LogLevel       slog.Level
+	Trace          bool
+	Log            bool
+	ReqDuration    bool
+	ReqSize        bool
+	RespSize       bool
+	CodeAttr       bool
+	AttrsFromCtx   bool
+}
+
+// TransportOption is a function that configures the client transport.
+type TransportOption func(*transportOpts)
+
+// WithTransportSkipper sets the skipper for the transport. Skipped requests aren't traced, measured and logged.
+func WithTransportSkipper(skipper Skipper) TransportOption {
+	return func(opts *transportOpts) {
+		opts.Skipper = skipper
+	}
+}
+
+// WithTransportTracerProvider sets the tracer provider to start client spans with.
+// The global tracer provider (otel.GetTracerProvider) is used by default.
+func WithTransportTracerProvider(tp trace.TracerProvider) TransportOption {
+	return func(opts *transportOpts) {
+		opts.TracerProvider = tp
+	}
+}
+
+// WithTransportPropagator sets the propagator to inject the trace context with.
+// The global propagator (otel.GetTextMapPropagator) is used by default.
+func WithTransportPropagator(p propagation.TextMapPropagator) TransportOption {
+	return func(opts *transportOpts) {
+		opts.Propagator = p
+	}
+}
+
+// WithTransportLogLevel sets the level to log requests with.
+// slog.LevelDebug is used by default.
+func WithTransportLogLevel(lvl slog.Level) TransportOption {
+	return func(opts *transportOpts) {
+		opts.LogLevel = lvl
+	}
+}
+
+// WithTransportCodeMeterAttr adds the response status code attribute to the metrics.
+func WithTransportCodeMeterAttr() TransportOption {
+	return func(opts *transportOpts) {
+		opts.CodeAttr = true
+	}
+}
+
+// WithTransportMetricNames configures the default metric names.
+func WithTransportMetricNames(names TransportMetricNames) TransportOption {
+	return func(opts *transportOpts) {
+		if names.ReqDurationHist != "" {
+			opts.Names.ReqDurationHist = names.ReqDurationHist
+		}
+		if names.ReqSizeHist != "" {
+			opts.Names.ReqSizeHist = names.ReqSizeHist
+		}
+		if names.RespSizeHist != "" {
+			opts.Names.RespSizeHist = names.RespSizeHist
+		}
+	}
+}
+
+// WithoutTransportTrace disables the trace context injection and client spans.
+func WithoutTransportTrace() TransportOption {
+	return func(opts *transportOpts) {
+		opts.Trace = false
+	}
+}
+
+// WithoutTransportLog disables logging of the requests.
+func WithoutTransportLog() TransportOption {
+	return func(opts *transportOpts) {
+		opts.Log = false
+	}
+}
+
+func WithoutTransportReqDurationMetric() TransportOption {
+	return func(opts *transportOpts) {
+		opts.ReqDuration = false
+	}
+}
+
+func WithoutTransportReqSizeMetric() TransportOption {
+	return func(opts *transportOpts) {
+		opts.ReqSize = false
+	}
+}
+
+func WithoutTransportRespSizeMetric() TransportOption {
+	return func(opts *transportOpts) {
+		opts.RespSize = false
+	}
+}
+
+func WithoutTransportAttrsFromCtx() TransportOption {
+	return func(opts *transportOpts) {
+		opts.AttrsFromCtx = false
+	}
+}
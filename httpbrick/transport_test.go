@@ -0,0 +1,197 @@
+package httpbrick
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/errbrick"
+	"github.com/demeero/bricks/otelbrick"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(prev) })
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	var gotTraceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotTraceparent = req.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	transport, err := NewTransport(nil,
+		WithTransportTracerProvider(tp),
+		WithTransportPropagator(propagation.TraceContext{}),
+		WithTransportCodeMeterAttr())
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/users", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, req.Header.Get("traceparent"), "the original request must not be modified")
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, gotTraceparent, span.SpanContext().TraceID().String())
+	assert.Contains(t, gotTraceparent, span.SpanContext().SpanID().String())
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var names []string
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != "bricks/httpbrick/transport" {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"http.client.request.duration",
+		"http.client.request.body.size",
+		"http.client.response.body.size",
+	}, names)
+}
+
+func TestTransport_RoundTrip_SharedCtx(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(prev) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport, err := NewTransport(nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	// the attributes slice of the context has a spare capacity (len 5, cap 8)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		ctx = otelbrick.AttrsToCtx(ctx, []attribute.KeyValue{attribute.Int("attr"+strconv.Itoa(i), i)})
+	}
+	const calls = 20
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		method := http.MethodGet
+		if i%2 == 1 {
+			method = http.MethodPut
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, method, srv.URL, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp, err := client.Do(req)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := make(map[string]uint64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.client.request.duration" {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[int64])
+			require.True(t, ok)
+			for _, dp := range hist.DataPoints {
+				assert.Equal(t, 5+3, dp.Attributes.Len())
+				method, _ := dp.Attributes.Value(semconv.HTTPRequestMethodKey)
+				counts[method.AsString()] += dp.Count
+			}
+		}
+	}
+	assert.Equal(t, map[string]uint64{http.MethodGet: calls / 2, http.MethodPut: calls / 2}, counts)
+}
+
+func TestErrFromResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           int
+		retryAfter     string
+		wantErr        error
+		wantRetryAfter time.Duration
+		wantNil        bool
+	}{
+		{name: "ErrFromResponse_WithOK_ReturnsNil", code: http.StatusOK, wantNil: true},
+		{name: "ErrFromResponse_WithNotFound_ReturnsErrNotFound", code: http.StatusNotFound, wantErr: errbrick.ErrNotFound},
+		{name: "ErrFromResponse_WithConflict_ReturnsErrConflict", code: http.StatusConflict, wantErr: errbrick.ErrConflict},
+		{
+			name:           "ErrFromResponse_WithRetryAfter_ReturnsDetailedErr",
+			code:           http.StatusTooManyRequests,
+			retryAfter:     "5",
+			wantErr:        errbrick.ErrRateLimited,
+			wantRetryAfter: 5 * time.Second,
+		},
+		{name: "ErrFromResponse_WithUnknownStatus_ReturnsErr", code: http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/users", nil)
+			resp := &http.Response{
+				StatusCode: tt.code,
+				Status:     http.StatusText(tt.code),
+				Header:     http.Header{},
+				Request:    req,
+			}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			err := ErrFromResponse(resp)
+			if tt.wantNil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tt.wantErr == nil {
+				assert.False(t, errbrick.IsOneOf(err))
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantRetryAfter > 0 {
+				detailed, ok := errbrick.AsDetailed(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.wantRetryAfter, detailed.RetryAfter)
+			}
+		})
+	}
+}
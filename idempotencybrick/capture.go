@@ -0,0 +1,77 @@
+package idempotencybrick
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/felixge/httpsnoop"
+)
+
+// Capture captures the response written to the wrapped http.ResponseWriter.
+type Capture struct {
+	w      http.ResponseWriter
+	before http.Header
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+// NewCapture wraps w to capture the response status, headers and body.
+// Only the headers added or changed after NewCapture are captured,
+// the ones already set by the outer middlewares (e.g. X-Request-ID, RateLimit-*) belong to the original request.
+// The wrapped writer keeps the optional interfaces of w (e.g. http.Flusher) via httpsnoop.
+func NewCapture(w http.ResponseWriter) (http.ResponseWriter, *Capture) {
+	c := &Capture{w: w, before: w.Header().Clone()}
+	wrapped := httpsnoop.Wrap(w, httpsnoop.Hooks{
+		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return func(code int) {
+				c.writeHeader(code)
+				next(code)
+			}
+		},
+		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return func(b []byte) (int, error) {
+				c.writeHeader(http.StatusOK)
+				n, err := next(b)
+				c.body.Write(b[:n])
+				return n, err
+			}
+		},
+		ReadFrom: func(next httpsnoop.ReadFromFunc) httpsnoop.ReadFromFunc {
+			return func(src io.Reader) (int64, error) {
+				c.writeHeader(http.StatusOK)
+				return next(io.TeeReader(src, &c.body))
+			}
+		},
+	})
+	return wrapped, c
+}
+
+func (c *Capture) writeHeader(code int) {
+	if c.status != 0 {
+		return
+	}
+	c.status = code
+	c.header = make(http.Header)
+	for name, values := range c.w.Header() {
+		if !slices.Equal(values, c.before[name]) {
+			c.header[name] = slices.Clone(values)
+		}
+	}
+}
+
+// Record returns the captured response as a Record.
+// If nothing was written, the status is 200 as net/http responds.
+func (c *Capture) Record(fingerprint string) Record {
+	if c.status == 0 {
+		c.writeHeader(http.StatusOK)
+	}
+	return Record{
+		Fingerprint: fingerprint,
+		Status:      c.status,
+		Header:      c.header,
+		Body:        c.body.Bytes(),
+	}
+}
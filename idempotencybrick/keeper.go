@@ -0,0 +1,166 @@
+// Package idempotencybrick provides the building blocks to replay the responses of the repeated requests
+// with the same Idempotency-Key (see httpbrick.IdempotencyMW and echobrick.IdempotencyMW).
+package idempotencybrick
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+// ReplayedHeader is set to "true" on the replayed responses.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// Option is a function that configures the keeper.
+type Option func(*keeperOpts)
+
+type keeperOpts struct {
+	Header  string
+	Methods []string
+	TTL     time.Duration
+	LockTTL time.Duration
+}
+
+// WithHeader sets the header to read the idempotency key from.
+// Idempotency-Key is used by default.
+func WithHeader(header string) Option {
+	return func(opts *keeperOpts) {
+		opts.Header = header
+	}
+}
+
+// WithMethods sets the HTTP methods the idempotency keys are handled for.
+// POST and PATCH are used by default.
+func WithMethods(methods ...string) Option {
+	return func(opts *keeperOpts) {
+		opts.Methods = methods
+	}
+}
+
+// WithTTL sets how long the completed responses are stored.
+// 24h is used by default.
+func WithTTL(ttl time.Duration) Option {
+	return func(opts *keeperOpts) {
+		opts.TTL = ttl
+	}
+}
+
+// WithLockTTL sets how long the key is locked by the in-progress request.
+// It bounds the lock if the instance dies in the middle of the request.
+// 1m is used by default.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(opts *keeperOpts) {
+		opts.LockTTL = ttl
+	}
+}
+
+// Keeper keeps the idempotent requests in the store.
+type Keeper struct {
+	store Store
+	opts  keeperOpts
+}
+
+// NewKeeper creates a new Keeper.
+func NewKeeper(store Store, options ...Option) *Keeper {
+	opts := keeperOpts{
+		Header:  "Idempotency-Key",
+		Methods: []string{http.MethodPost, http.MethodPatch},
+		TTL:     24 * time.Hour,
+		LockTTL: time.Minute,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &Keeper{store: store, opts: opts}
+}
+
+// Key returns the idempotency key of the request scoped by the subject (e.g. JWT subject).
+// It returns an empty string if the request has no key, its method isn't handled or the subject is empty:
+// anonymous callers would share the keys, so a caller could replay the response of another one.
+func (k *Keeper) Key(req *http.Request, subject string) string {
+	key := req.Header.Get(k.opts.Header)
+	if key == "" || subject == "" || !slices.Contains(k.opts.Methods, req.Method) {
+		return ""
+	}
+	return subject + ":" + key
+}
+
+// Fingerprint returns the hash of the request method, URI and body.
+// The body is read and replaced, so the request can be handled after that.
+func Fingerprint(req *http.Request) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed read req body: %w", err)
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Begin locks the key for the request with the fingerprint.
+// It returns nil if the request must be handled, then Complete must be called with its response.
+// It returns the completed record to replay if the request was already handled.
+// It returns the error wrapping errbrick.ErrConflict if the key is reused with another fingerprint
+// or the request with the key is still in progress.
+func (k *Keeper) Begin(ctx context.Context, key, fingerprint string) (*Record, error) {
+	rec, locked, err := k.store.Lock(ctx, key, fingerprint, k.opts.LockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed lock idempotency key: %w", err)
+	}
+	if locked {
+		return nil, nil
+	}
+	if rec.Fingerprint != fingerprint {
+		return nil, errbrick.NewDetailed(errbrick.ErrConflict, "idempotency key is reused with a different request",
+			errbrick.WithReason("IDEMPOTENCY_KEY_REUSED"))
+	}
+	if !rec.Completed {
+		return nil, errbrick.NewDetailed(errbrick.ErrConflict, "request with the same idempotency key is in progress",
+			errbrick.WithReason("IDEMPOTENCY_KEY_IN_PROGRESS"), errbrick.WithRetryAfter(time.Second))
+	}
+	return &rec, nil
+}
+
+// Complete stores the response of the request locked by Begin.
+// The server errors (5xx) aren't stored and unlock the key, so the request can be retried.
+func (k *Keeper) Complete(ctx context.Context, key string, rec Record) error {
+	if rec.Status >= http.StatusInternalServerError {
+		if err := k.store.Unlock(ctx, key); err != nil {
+			return fmt.Errorf("failed unlock idempotency key: %w", err)
+		}
+		return nil
+	}
+	rec.Completed = true
+	if err := k.store.Save(ctx, key, rec, k.opts.TTL); err != nil {
+		return fmt.Errorf("failed save idempotent resp: %w", err)
+	}
+	return nil
+}
+
+// Replay writes the stored response.
+// The stored headers are only the ones set by the handler (see NewCapture),
+// so the headers set by the outer middlewares for this request (e.g. X-Request-ID) are kept.
+func Replay(w http.ResponseWriter, rec *Record) error {
+	for name, values := range rec.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	if _, err := w.Write(rec.Body); err != nil {
+		return fmt.Errorf("failed write replayed resp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package idempotencybrick
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestKeeper_Key(t *testing.T) {
+	k := NewKeeper(NewMemoryStore())
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	assert.Empty(t, k.Key(req, "user-1"))
+
+	req.Header.Set("Idempotency-Key", "abc")
+	assert.Equal(t, "user-1:abc", k.Key(req, "user-1"))
+
+	req.Method = http.MethodGet
+	assert.Empty(t, k.Key(req, "user-1"))
+
+	req.Method = http.MethodPost
+	assert.Empty(t, k.Key(req, ""))
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(method, target, body string) string {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		fp, err := Fingerprint(req)
+		require.NoError(t, err)
+		restored, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(restored))
+		return fp
+	}
+
+	fp := fingerprint(http.MethodPost, "/orders", `{"qty":1}`)
+	assert.Equal(t, fp, fingerprint(http.MethodPost, "/orders", `{"qty":1}`))
+	assert.NotEqual(t, fp, fingerprint(http.MethodPost, "/orders", `{"qty":2}`))
+	assert.NotEqual(t, fp, fingerprint(http.MethodPost, "/orders?dry_run=true", `{"qty":1}`))
+	assert.NotEqual(t, fp, fingerprint(http.MethodPatch, "/orders", `{"qty":1}`))
+}
+
+func TestKeeper_BeginComplete(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(NewMemoryStore())
+
+	rec, err := k.Begin(ctx, "k1", "fp1")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	_, err = k.Begin(ctx, "k1", "fp1")
+	require.ErrorIs(t, err, errbrick.ErrConflict)
+	detailed, ok := errbrick.AsDetailed(err)
+	require.True(t, ok)
+	assert.Equal(t, time.Second, detailed.RetryAfter)
+
+	require.NoError(t, k.Complete(ctx, "k1", Record{Fingerprint: "fp1", Status: http.StatusCreated, Body: []byte("ok")}))
+	rec, err = k.Begin(ctx, "k1", "fp1")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, http.StatusCreated, rec.Status)
+	assert.Equal(t, []byte("ok"), rec.Body)
+
+	_, err = k.Begin(ctx, "k1", "fp2")
+	require.ErrorIs(t, err, errbrick.ErrConflict)
+
+	// server errors unlock the key
+	_, err = k.Begin(ctx, "k2", "fp1")
+	require.NoError(t, err)
+	require.NoError(t, k.Complete(ctx, "k2", Record{Fingerprint: "fp1", Status: http.StatusBadGateway}))
+	rec, err = k.Begin(ctx, "k2", "fp1")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestMemoryStore_Expiration(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	_, locked, err := s.Lock(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	require.True(t, locked)
+	_, locked, err = s.Lock(ctx, "k", "fp", time.Minute)
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	now = now.Add(2 * time.Minute)
+	require.Error(t, s.Save(ctx, "k", Record{Fingerprint: "fp", Completed: true}, time.Hour))
+	_, locked, err = s.Lock(ctx, "k", "fp2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	// the key is locked by another request
+	require.Error(t, s.Save(ctx, "k", Record{Fingerprint: "fp", Completed: true}, time.Hour))
+	require.NoError(t, s.Save(ctx, "k", Record{Fingerprint: "fp2", Completed: true}, time.Hour))
+	require.Error(t, s.Save(ctx, "k", Record{Fingerprint: "fp2", Completed: true}, time.Hour))
+	require.Error(t, s.Save(ctx, "missing", Record{Fingerprint: "fp", Completed: true}, time.Hour))
+}
+
+func TestCaptureReplay(t *testing.T) {
+	rr := httptest.NewRecorder()
+	rr.Header().Set("X-Request-ID", "req-1")
+	rr.Header().Set("Vary", "Origin")
+	w, capture := NewCapture(rr)
+	w.Header().Set("Location", "/orders/1")
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte("ok"))
+
+	rec := capture.Record("fp")
+	assert.Equal(t, http.Header{"Location": {"/orders/1"}, "Vary": {"Origin", "Accept"}}, rec.Header)
+
+	replayed := httptest.NewRecorder()
+	replayed.Header().Set("X-Request-ID", "req-2")
+	require.NoError(t, Replay(replayed, &rec))
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "req-2", replayed.Header().Get("X-Request-ID"))
+	assert.Equal(t, "/orders/1", replayed.Header().Get("Location"))
+	assert.Equal(t, "true", replayed.Header().Get(ReplayedHeader))
+	assert.Equal(t, "ok", replayed.Body.String())
+}
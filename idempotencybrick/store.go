@@ -0,0 +1,99 @@
+package idempotencybrick
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record is a stored idempotent request.
+// The record is in progress (not Completed) while the first request is being handled.
+type Record struct {
+	Header      http.Header
+	Fingerprint string
+	Body        []byte
+	Status      int
+	Completed   bool
+}
+
+// Store stores the idempotent requests.
+// Implement it to share the records between the instances (see cqlbrick.IdempotencyStore).
+type Store interface {
+	// Lock atomically saves the in-progress record with the fingerprint for ttl if there is no record for the key.
+	// If the key is already taken, the existing record and false are returned.
+	Lock(ctx context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error)
+	// Save saves the completed record for ttl.
+	// It fails if the key isn't locked for the record fingerprint anymore (e.g. the lock expired).
+	Save(ctx context.Context, key string, rec Record, ttl time.Duration) error
+	// Unlock deletes the record, so the request can be retried.
+	Unlock(ctx context.Context, key string) error
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	rec       Record
+}
+
+// MemoryStore is an in-memory Store. The expired records are swept periodically on locks.
+type MemoryStore struct {
+	now       func() time.Time
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Lock implements Store.
+func (s *MemoryStore) Lock(_ context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[key]; ok && !now.After(entry.expiresAt) {
+		return entry.rec, false, nil
+	}
+	rec := Record{Fingerprint: fingerprint}
+	s.entries[key] = memoryEntry{rec: rec, expiresAt: now.Add(ttl)}
+	return rec, true, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, key string, rec Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expiresAt) || entry.rec.Completed || entry.rec.Fingerprint != rec.Fingerprint {
+		return errors.New("idempotency key lock expired")
+	}
+	s.entries[key] = memoryEntry{rec: rec, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Unlock implements Store.
+func (s *MemoryStore) Unlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep removes the expired entries not more often than once a minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package jwtbrick
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/demeero/bricks/otelbrick"
+)
+
+// ClaimsToBaggage sets the claims to the W3C baggage in the context (see otelbrick.SetBaggage),
+// so they are propagated to the downstream services.
+// The mapping maps the claim names to the baggage keys, e.g. {"tenant_id": "tenant.id"}.
+// Only string and number claims are set, the missing and other claims are skipped.
+// The mapped keys already in the baggage (e.g. sent by the client) are deleted first,
+// so the client can't spoof them even if the token lacks the claim.
+func ClaimsToBaggage(ctx context.Context, claims jwt.MapClaims, mapping map[string]string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+	for _, key := range mapping {
+		bag = bag.DeleteMember(key)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+
+	members := make(map[string]string, len(mapping))
+	for claim, key := range mapping {
+		switch v := claims[claim].(type) {
+		case string:
+			members[key] = v
+		case float64:
+			members[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	if len(members) == 0 {
+		return ctx, nil
+	}
+	return otelbrick.SetBaggage(ctx, members)
+}
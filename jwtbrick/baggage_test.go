@@ -0,0 +1,32 @@
+package jwtbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestClaimsToBaggage(t *testing.T) {
+	clientBag, err := baggage.Parse("tenant.id=spoofed,missing=spoofed,region=eu")
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(context.Background(), clientBag)
+
+	claims := jwt.MapClaims{"tenant_id": "acme", "plan_id": float64(42), "roles": []interface{}{"admin"}}
+	ctx, err = ClaimsToBaggage(ctx, claims, map[string]string{
+		"tenant_id": "tenant.id",
+		"plan_id":   "plan.id",
+		"roles":     "roles",
+		"missing":   "missing",
+	})
+	require.NoError(t, err)
+	bag := baggage.FromContext(ctx)
+	assert.Equal(t, 3, bag.Len())
+	assert.Equal(t, "acme", bag.Member("tenant.id").Value())
+	assert.Equal(t, "42", bag.Member("plan.id").Value())
+	assert.Equal(t, "eu", bag.Member("region").Value())
+	assert.Empty(t, bag.Member("missing").Key())
+}
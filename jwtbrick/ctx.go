@@ -0,0 +1,50 @@
+package jwtbrick
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+type claimsCtxKey struct{}
+
+var claimsKey = claimsCtxKey{}
+
+// ClaimsToCtx adds the token claims to the context.
+func ClaimsToCtx(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromCtx returns the token claims from the context.
+// If there are no claims in the context, it returns empty claims.
+func ClaimsFromCtx(ctx context.Context) jwt.MapClaims {
+	claims, ok := ctx.Value(claimsKey).(jwt.MapClaims)
+	if !ok {
+		return jwt.MapClaims{}
+	}
+	return claims
+}
+
+// TypedClaimsFromCtx decodes the token claims from the context into the user-defined claims struct T.
+// T is decoded via JSON, so its fields should have json tags matching the claim names.
+// Embed jwt.RegisteredClaims into T to get the standard claims.
+// It returns errbrick.ErrUnauthenticated if there are no claims in the context.
+func TypedClaimsFromCtx[T any](ctx context.Context) (T, error) {
+	var typed T
+	claims, ok := ctx.Value(claimsKey).(jwt.MapClaims)
+	if !ok {
+		return typed, fmt.Errorf("%w: no token claims", errbrick.ErrUnauthenticated)
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return typed, fmt.Errorf("failed marshal token claims: %w", err)
+	}
+	if err := json.Unmarshal(b, &typed); err != nil {
+		return typed, fmt.Errorf("%w: failed decode token claims: %w", errbrick.ErrUnauthenticated, err)
+	}
+	return typed, nil
+}
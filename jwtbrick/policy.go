@@ -0,0 +1,115 @@
+package jwtbrick
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+// Policy is an authorization rule that is checked against the token claims.
+// It returns errbrick.ErrForbidden (or a wrapped one) if the claims don't satisfy the rule.
+type Policy func(claims jwt.MapClaims) error
+
+// Authorize checks the token claims from the context against all policies.
+// It returns errbrick.ErrUnauthenticated if there are no claims in the context
+// and the first policy error otherwise.
+func Authorize(ctx context.Context, policies ...Policy) error {
+	claims, ok := ctx.Value(claimsKey).(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("%w: no token claims", errbrick.ErrUnauthenticated)
+	}
+	for _, policy := range policies {
+		if err := policy(claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RequireScopes requires all the scopes to be granted.
+// Scopes are read from "scope" (space-delimited string) and "scp" (string or array) claims.
+func RequireScopes(scopes ...string) Policy {
+	return func(claims jwt.MapClaims) error {
+		granted := Scopes(claims)
+		for _, scope := range scopes {
+			if !slices.Contains(granted, scope) {
+				return fmt.Errorf("%w: missing scope %q", errbrick.ErrForbidden, scope)
+			}
+		}
+		return nil
+	}
+}
+
+// RequireRoles requires at least one of the roles to be granted.
+// Roles are read from "roles" claim (string or array).
+func RequireRoles(roles ...string) Policy {
+	return func(claims jwt.MapClaims) error {
+		granted := Roles(claims)
+		for _, role := range roles {
+			if slices.Contains(granted, role) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: one of roles %q required", errbrick.ErrForbidden, roles)
+	}
+}
+
+// RequireClaim requires the claim to be present and satisfy the predicate.
+func RequireClaim(name string, predicate func(value interface{}) bool) Policy {
+	return func(claims jwt.MapClaims) error {
+		value, ok := claims[name]
+		if !ok || !predicate(value) {
+			return fmt.Errorf("%w: claim %q doesn't satisfy the policy", errbrick.ErrForbidden, name)
+		}
+		return nil
+	}
+}
+
+// AnyOf requires at least one of the policies to be satisfied.
+// If none is satisfied, the error of the last policy is returned.
+func AnyOf(policies ...Policy) Policy {
+	return func(claims jwt.MapClaims) error {
+		err := fmt.Errorf("%w: no policies", errbrick.ErrForbidden)
+		for _, policy := range policies {
+			if err = policy(claims); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// Scopes returns the scopes granted by "scope" and "scp" claims.
+func Scopes(claims jwt.MapClaims) []string {
+	return append(stringsClaim(claims, "scope"), stringsClaim(claims, "scp")...)
+}
+
+// Roles returns the roles granted by "roles" claim.
+func Roles(claims jwt.MapClaims) []string {
+	return stringsClaim(claims, "roles")
+}
+
+// stringsClaim reads the claim that is either a space-delimited string or an array of strings.
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package jwtbrick
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func TestAuthorize(t *testing.T) {
+	claims := jwt.MapClaims{
+		"sub":    "user-1",
+		"scope":  "users:read users:write",
+		"scp":    []interface{}{"orders:read"},
+		"roles":  []interface{}{"editor", "viewer"},
+		"tenant": "t1",
+	}
+	tests := []struct {
+		name     string
+		policies []Policy
+		wantErr  error
+	}{
+		{
+			name:     "scopes",
+			policies: []Policy{RequireScopes("users:read", "orders:read")},
+		},
+		{
+			name:     "missing-scope",
+			policies: []Policy{RequireScopes("users:read", "users:delete")},
+			wantErr:  errbrick.ErrForbidden,
+		},
+		{
+			name:     "any-role",
+			policies: []Policy{RequireRoles("admin", "editor")},
+		},
+		{
+			name:     "missing-role",
+			policies: []Policy{RequireRoles("admin")},
+			wantErr:  errbrick.ErrForbidden,
+		},
+		{
+			name:     "claim",
+			policies: []Policy{RequireClaim("tenant", func(v interface{}) bool { return v == "t1" })},
+		},
+		{
+			name:     "claim-mismatch",
+			policies: []Policy{RequireClaim("tenant", func(v interface{}) bool { return v == "t2" })},
+			wantErr:  errbrick.ErrForbidden,
+		},
+		{
+			name:     "missing-claim",
+			policies: []Policy{RequireClaim("org", func(v interface{}) bool { return true })},
+			wantErr:  errbrick.ErrForbidden,
+		},
+		{
+			name:     "any-of",
+			policies: []Policy{AnyOf(RequireRoles("admin"), RequireScopes("users:write"))},
+		},
+		{
+			name:     "any-of-none",
+			policies: []Policy{AnyOf(RequireRoles("admin"), RequireScopes("users:delete"))},
+			wantErr:  errbrick.ErrForbidden,
+		},
+		{
+			name:     "all",
+			policies: []Policy{RequireScopes("users:read"), RequireRoles("admin")},
+			wantErr:  errbrick.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(ClaimsToCtx(context.Background(), claims), tt.policies...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorize_NoClaims(t *testing.T) {
+	err := Authorize(context.Background(), RequireScopes("users:read"))
+	assert.ErrorIs(t, err, errbrick.ErrUnauthenticated)
+}
+
+func TestTypedClaimsFromCtx(t *testing.T) {
+	type userClaims struct {
+		Scope  string   `json:"scope"`
+		Roles  []string `json:"roles"`
+		Tenant string   `json:"tenant"`
+		jwt.RegisteredClaims
+	}
+	ctx := ClaimsToCtx(context.Background(), jwt.MapClaims{
+		"sub":    "user-1",
+		"exp":    float64(1700000000),
+		"scope":  "users:read",
+		"roles":  []interface{}{"editor"},
+		"tenant": "t1",
+	})
+
+	claims, err := TypedClaimsFromCtx[userClaims](ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, int64(1700000000), claims.ExpiresAt.Unix())
+	assert.Equal(t, []string{"editor"}, claims.Roles)
+	assert.Equal(t, "t1", claims.Tenant)
+
+	_, err = TypedClaimsFromCtx[userClaims](context.Background())
+	assert.ErrorIs(t, err, errbrick.ErrUnauthenticated)
+	assert.Empty(t, ClaimsFromCtx(context.Background()))
+}
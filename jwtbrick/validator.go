@@ -0,0 +1,273 @@
+package jwtbrick
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+var (
+	hmacAlgs       = []string{"HS256", "HS384", "HS512"}
+	asymmetricAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
+// ValidatorOption is a function that configures Validator.
+type ValidatorOption func(*validatorOpts)
+
+type validatorOpts struct {
+	KeyFuncOpts        keyfunc.Options
+	JWKSURL            string
+	Issuer             string
+	Audience           string
+	JWKS               []byte
+	HMACSecret         []byte
+	PEMKeys            [][]byte
+	Algorithms         []string
+	RequiredClaims     []string
+	Leeway             time.Duration
+	ExpirationRequired bool
+}
+
+// WithJWKSURL sets the URL to retrieve JWKS from.
+// The JWKS is refreshed in the background (hourly by default and on unknown "kid"), see WithKeyFuncOpts to change it.
+func WithJWKSURL(url string) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.JWKSURL = url
+	}
+}
+
+// WithKeyFuncOpts sets the options for the JWKS retrieved via WithJWKSURL.
+func WithKeyFuncOpts(keyFuncOpts keyfunc.Options) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.KeyFuncOpts = keyFuncOpts
+	}
+}
+
+// WithJWKS sets a static JWKS in JSON format.
+func WithJWKS(jwks []byte) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.JWKS = jwks
+	}
+}
+
+// WithPEMPublicKey adds a static RSA, ECDSA or Ed25519 public key in PEM format.
+// The option can be used multiple times to add several keys, e.g. during key rotation.
+func WithPEMPublicKey(key []byte) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.PEMKeys = append(opts.PEMKeys, key)
+	}
+}
+
+// WithHMACSecret sets the shared secret for HMAC signed tokens.
+func WithHMACSecret(secret []byte) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.HMACSecret = secret
+	}
+}
+
+// WithIssuer sets the expected "iss" claim.
+func WithIssuer(iss string) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.Issuer = iss
+	}
+}
+
+// WithAudience sets the expected "aud" claim.
+func WithAudience(aud string) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.Audience = aud
+	}
+}
+
+// WithAlgorithms sets the allowed signing algorithms.
+// By default, HS256/384/512 are allowed for HMAC secret and all asymmetric algorithms for public keys.
+func WithAlgorithms(algs ...string) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.Algorithms = algs
+	}
+}
+
+// WithRequiredClaims sets the claims that must be present in the token.
+func WithRequiredClaims(claims ...string) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.RequiredClaims = claims
+	}
+}
+
+// WithLeeway sets the allowed clock skew for "exp", "nbf" and "iat" claims.
+func WithLeeway(leeway time.Duration) ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.Leeway = leeway
+	}
+}
+
+// WithoutExpirationRequired allows tokens without "exp" claim.
+func WithoutExpirationRequired() ValidatorOption {
+	return func(opts *validatorOpts) {
+		opts.ExpirationRequired = false
+	}
+}
+
+// Validator verifies JWT signatures and validates the claims.
+type Validator struct {
+	keyFunc        jwt.Keyfunc
+	jwks           *keyfunc.JWKS
+	parser         *jwt.Parser
+	requiredClaims []string
+}
+
+// NewValidator creates a new Validator.
+// Exactly one key source must be configured: JWKS URL, static keys (JWKS and/or PEM) or HMAC secret.
+// It returns an error if the keys can't be loaded, so the service never falls back to accepting unsigned tokens.
+func NewValidator(options ...ValidatorOption) (*Validator, error) {
+	opts := validatorOpts{ExpirationRequired: true}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	v := &Validator{requiredClaims: opts.RequiredClaims}
+	algs := opts.Algorithms
+	if err := v.initKeyFunc(opts); err != nil {
+		return nil, err
+	}
+	if len(algs) == 0 {
+		algs = asymmetricAlgs
+		if len(opts.HMACSecret) > 0 {
+			algs = hmacAlgs
+		}
+	}
+
+	parserOpts := []jwt.ParserOption{jwt.WithValidMethods(algs), jwt.WithLeeway(opts.Leeway)}
+	if opts.ExpirationRequired {
+		parserOpts = append(parserOpts, jwt.WithExpirationRequired())
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+	v.parser = jwt.NewParser(parserOpts...)
+	return v, nil
+}
+
+func (v *Validator) initKeyFunc(opts validatorOpts) error {
+	var sources int
+	if opts.JWKSURL != "" {
+		sources++
+	}
+	if len(opts.JWKS) > 0 || len(opts.PEMKeys) > 0 {
+		sources++
+	}
+	if len(opts.HMACSecret) > 0 {
+		sources++
+	}
+	switch {
+	case sources == 0:
+		return errors.New("no JWT key source configured")
+	case sources > 1:
+		return errors.New("multiple JWT key sources configured")
+	}
+
+	switch {
+	case opts.JWKSURL != "":
+		keyFuncOpts := opts.KeyFuncOpts
+		if keyFuncOpts.RefreshInterval == 0 {
+			keyFuncOpts.RefreshInterval = time.Hour
+		}
+		if keyFuncOpts.RefreshRateLimit == 0 {
+			keyFuncOpts.RefreshRateLimit = 5 * time.Minute
+			keyFuncOpts.RefreshUnknownKID = true
+		}
+		if keyFuncOpts.RefreshErrorHandler == nil {
+			keyFuncOpts.RefreshErrorHandler = func(err error) {
+				slog.Error("failed refresh JWKS", slog.Any("err", err), slog.String("jwks_url", opts.JWKSURL))
+			}
+		}
+		jwks, err := keyfunc.Get(opts.JWKSURL, keyFuncOpts)
+		if err != nil {
+			return fmt.Errorf("failed get JWKS from %s: %w", opts.JWKSURL, err)
+		}
+		v.jwks = jwks
+		v.keyFunc = jwks.Keyfunc
+	case len(opts.HMACSecret) > 0:
+		secret := opts.HMACSecret
+		v.keyFunc = func(*jwt.Token) (interface{}, error) { return secret, nil }
+	default:
+		keyFunc, err := staticKeyFunc(opts.JWKS, opts.PEMKeys)
+		if err != nil {
+			return err
+		}
+		v.keyFunc = keyFunc
+	}
+	return nil
+}
+
+func staticKeyFunc(jwksJSON []byte, pemKeys [][]byte) (jwt.Keyfunc, error) {
+	var keys []jwt.VerificationKey
+	if len(jwksJSON) > 0 {
+		jwks, err := keyfunc.NewJSON(json.RawMessage(jwksJSON))
+		if err != nil {
+			return nil, fmt.Errorf("failed parse JWKS: %w", err)
+		}
+		if len(pemKeys) == 0 {
+			return jwks.Keyfunc, nil
+		}
+		for _, key := range jwks.ReadOnlyKeys() {
+			keys = append(keys, key)
+		}
+	}
+	for i, pemKey := range pemKeys {
+		key, err := parsePEMPublicKey(pemKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed parse PEM public key #%d: %w", i, err)
+		}
+		keys = append(keys, key)
+	}
+	return func(*jwt.Token) (interface{}, error) {
+		return jwt.VerificationKeySet{Keys: keys}, nil
+	}, nil
+}
+
+func parsePEMPublicKey(key []byte) (jwt.VerificationKey, error) {
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(key); err == nil {
+		return rsaKey, nil
+	}
+	if ecKey, err := jwt.ParseECPublicKeyFromPEM(key); err == nil {
+		return ecKey, nil
+	}
+	edKey, err := jwt.ParseEdPublicKeyFromPEM(key)
+	if err != nil {
+		return nil, errors.New("unsupported key type: RSA, ECDSA or Ed25519 public key expected")
+	}
+	return edKey, nil
+}
+
+// Validate verifies the token signature, validates the claims and returns them.
+// All returned errors are errbrick.ErrUnauthenticated.
+func (v *Validator) Validate(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", errbrick.ErrUnauthenticated, err)
+	}
+	for _, name := range v.requiredClaims {
+		if _, ok := claims[name]; !ok {
+			return nil, fmt.Errorf("%w: token is missing required claim %q", errbrick.ErrUnauthenticated, name)
+		}
+	}
+	return claims, nil
+}
+
+// Close stops the background JWKS refresh if any.
+func (v *Validator) Close() {
+	if v.jwks != nil {
+		v.jwks.EndBackground()
+	}
+}
@@ -0,0 +1,259 @@
+package jwtbrick
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func rsaJWKS(t *testing.T, kid string, key *rsa.PublicKey) []byte {
+	t.Helper()
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	return jwks
+}
+
+func pemPublicKey(t *testing.T, key interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	tkn := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tkn.Header["kid"] = kid
+	}
+	signed, err := tkn.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "https://issuer.test",
+		"aud": "api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestValidator_JWKSURL(t *testing.T) {
+	key := generateRSAKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(rsaJWKS(t, "k1", &key.PublicKey))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	v, err := NewValidator(WithJWKSURL(srv.URL), WithIssuer("https://issuer.test"), WithAudience("api"))
+	require.NoError(t, err)
+	defer v.Close()
+
+	claims, err := v.Validate(signToken(t, jwt.SigningMethodRS256, "k1", key, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims["sub"])
+
+	otherKey := generateRSAKey(t)
+	_, err = v.Validate(signToken(t, jwt.SigningMethodRS256, "k1", otherKey, validClaims()))
+	assert.ErrorIs(t, err, errbrick.ErrUnauthenticated)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestValidator_JWKSURLUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewValidator(WithJWKSURL(srv.URL))
+	assert.Error(t, err)
+}
+
+func TestValidator_StaticKeys(t *testing.T) {
+	rsaKey := generateRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		options []ValidatorOption
+		token   string
+	}{
+		{
+			name:    "JWKS",
+			options: []ValidatorOption{WithJWKS(rsaJWKS(t, "k1", &rsaKey.PublicKey))},
+			token:   signToken(t, jwt.SigningMethodRS256, "k1", rsaKey, validClaims()),
+		},
+		{
+			name:    "PEM-RSA",
+			options: []ValidatorOption{WithPEMPublicKey(pemPublicKey(t, &rsaKey.PublicKey))},
+			token:   signToken(t, jwt.SigningMethodRS256, "", rsaKey, validClaims()),
+		},
+		{
+			name: "PEM-ECDSA-rotation",
+			options: []ValidatorOption{
+				WithPEMPublicKey(pemPublicKey(t, &rsaKey.PublicKey)),
+				WithPEMPublicKey(pemPublicKey(t, &ecKey.PublicKey)),
+			},
+			token: signToken(t, jwt.SigningMethodES256, "", ecKey, validClaims()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.options...)
+			require.NoError(t, err)
+			claims, err := v.Validate(tt.token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims["sub"])
+		})
+	}
+}
+
+func TestValidator_Claims(t *testing.T) {
+	secret := []byte("secret")
+	v, err := NewValidator(WithHMACSecret(secret),
+		WithIssuer("https://issuer.test"),
+		WithAudience("api"),
+		WithRequiredClaims("sub"),
+		WithLeeway(time.Minute))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		key     interface{}
+		claims  func(jwt.MapClaims)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			method: jwt.SigningMethodHS256,
+			key:    secret,
+			claims: func(jwt.MapClaims) {},
+		},
+		{
+			name:   "expired-within-leeway",
+			method: jwt.SigningMethodHS256,
+			key:    secret,
+			claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-30 * time.Second).Unix() },
+		},
+		{
+			name:    "expired",
+			method:  jwt.SigningMethodHS256,
+			key:     secret,
+			claims:  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() },
+			wantErr: true,
+		},
+		{
+			name:    "without-exp",
+			method:  jwt.SigningMethodHS256,
+			key:     secret,
+			claims:  func(c jwt.MapClaims) { delete(c, "exp") },
+			wantErr: true,
+		},
+		{
+			name:    "wrong-issuer",
+			method:  jwt.SigningMethodHS256,
+			key:     secret,
+			claims:  func(c jwt.MapClaims) { c["iss"] = "https://evil.test" },
+			wantErr: true,
+		},
+		{
+			name:    "wrong-audience",
+			method:  jwt.SigningMethodHS256,
+			key:     secret,
+			claims:  func(c jwt.MapClaims) { c["aud"] = "other" },
+			wantErr: true,
+		},
+		{
+			name:    "missing-required-claim",
+			method:  jwt.SigningMethodHS256,
+			key:     secret,
+			claims:  func(c jwt.MapClaims) { delete(c, "sub") },
+			wantErr: true,
+		},
+		{
+			name:    "wrong-secret",
+			method:  jwt.SigningMethodHS256,
+			key:     []byte("other"),
+			claims:  func(jwt.MapClaims) {},
+			wantErr: true,
+		},
+		{
+			name:    "none-alg",
+			method:  jwt.SigningMethodNone,
+			key:     jwt.UnsafeAllowNoneSignatureType,
+			claims:  func(jwt.MapClaims) {},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.claims(claims)
+			_, err := v.Validate(signToken(t, tt.method, "", tt.key, claims))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errbrick.ErrUnauthenticated)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidator_Algorithms(t *testing.T) {
+	secret := []byte("secret")
+	v, err := NewValidator(WithHMACSecret(secret), WithAlgorithms("HS512"))
+	require.NoError(t, err)
+
+	_, err = v.Validate(signToken(t, jwt.SigningMethodHS256, "", secret, validClaims()))
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	_, err = v.Validate(signToken(t, jwt.SigningMethodHS512, "", secret, validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestNewValidator_KeySources(t *testing.T) {
+	_, err := NewValidator()
+	assert.EqualError(t, err, "no JWT key source configured")
+
+	_, err = NewValidator(WithHMACSecret([]byte("secret")), WithJWKSURL("http://localhost"))
+	assert.EqualError(t, err, "multiple JWT key sources configured")
+
+	_, err = NewValidator(WithPEMPublicKey([]byte("not a key")))
+	assert.Error(t, err)
+}
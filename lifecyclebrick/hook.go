@@ -0,0 +1,66 @@
+package lifecyclebrick
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Server is a server that blocks in Serve until it's shut down, e.g. httpbrick.Server or grpcbrick.Server.
+type Server interface {
+	Serve() error
+	Shutdown(ctx context.Context) error
+}
+
+// ServerHook creates a hook that serves the server and shuts it down on stop.
+func ServerHook(name string, srv Server) Hook {
+	return Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			return srv.Serve()
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// HTTPServerHook creates a hook that starts http.Server via ListenAndServe and gracefully shuts it down on stop.
+func HTTPServerHook(name string, srv *http.Server) Hook {
+	return Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// ShutdownHook creates a hook that calls the shutdown func on stop,
+// e.g. the funcs returned by otelbrick.InitTrace and otelbrick.InitMeter.
+func ShutdownHook(name string, shutdown func(ctx context.Context) error) Hook {
+	return Hook{Name: name, Stop: shutdown}
+}
+
+// CloserHook creates a hook that closes the closer on stop, e.g. watermill publishers and subscribers.
+func CloserHook(name string, closer io.Closer) Hook {
+	return Hook{
+		Name: name,
+		Stop: func(context.Context) error {
+			return closer.Close()
+		},
+	}
+}
+
+// CloseFuncHook creates a hook that calls the close func on stop, e.g. gocql.Session.Close.
+func CloseFuncHook(name string, closeFunc func()) Hook {
+	return Hook{
+		Name: name,
+		Stop: func(context.Context) error {
+			closeFunc()
+			return nil
+		},
+	}
+}
@@ -0,0 +1,160 @@
+// Package lifecyclebrick coordinates the startup and the ordered teardown of the application components
+// like servers, subscribers, DB sessions and OTEL providers.
+package lifecyclebrick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// Hook is a component of the application managed by the Runner.
+type Hook struct {
+	// Start starts the component. It may block until the component is stopped (e.g. a server)
+	// or return right after the start (e.g. a client connection).
+	// Returning an error stops the whole application.
+	Start func(ctx context.Context) error
+	// Stop stops the component. The context is done when the shutdown deadline is exceeded.
+	Stop func(ctx context.Context) error
+	// Name is used for logging.
+	Name string
+}
+
+// Option is a function that configures the Runner.
+type Option func(*runnerOpts)
+
+type runnerOpts struct {
+	signals         []os.Signal
+	shutdownTimeout time.Duration
+}
+
+// WithShutdownTimeout sets the deadline to stop all the hooks within.
+// 15s is used by default.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(opts *runnerOpts) {
+		opts.shutdownTimeout = d
+	}
+}
+
+// WithSignals sets the signals to stop the application on.
+// SIGINT and SIGTERM are used by default.
+func WithSignals(signals ...os.Signal) Option {
+	return func(opts *runnerOpts) {
+		opts.signals = signals
+	}
+}
+
+// Runner starts the hooks concurrently and stops them in reverse order.
+type Runner struct {
+	hooks []Hook
+	opts  runnerOpts
+}
+
+// New creates a new Runner.
+func New(options ...Option) *Runner {
+	opts := runnerOpts{
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		shutdownTimeout: 15 * time.Second,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &Runner{opts: opts}
+}
+
+// Append registers the hooks. The hooks are stopped in reverse order of registration,
+// so register the dependencies (e.g. OTEL providers, DB sessions) before the components using them (e.g. servers).
+func (r *Runner) Append(hooks ...Hook) {
+	r.hooks = append(r.hooks, hooks...)
+}
+
+// Run starts all the hooks concurrently and blocks until the context is done, a signal is received or any hook fails to start.
+// Then it stops the hooks in reverse order within the shutdown timeout.
+// It returns the start error (if any) joined with the stop errors.
+func (r *Runner) Run(ctx context.Context) error {
+	logger := slogbrick.FromCtx(ctx)
+	stopCtx, stopSignals := signal.NotifyContext(ctx, r.opts.signals...)
+	defer stopSignals()
+	// the hooks are started with the context that isn't canceled by the signal,
+	// so the components are stopped by the Stop hooks in the expected order.
+	startCtx, cancelStart := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelStart()
+
+	var (
+		wg       sync.WaitGroup
+		startErr error
+		errOnce  sync.Once
+		failed   = make(chan struct{})
+	)
+	for _, hook := range r.hooks {
+		if hook.Start == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(hook Hook) {
+			defer wg.Done()
+			logger.Info("starting component", slog.String("component", hook.Name))
+			if err := hook.Start(startCtx); err != nil {
+				errOnce.Do(func() {
+					startErr = fmt.Errorf("failed start %s: %w", hook.Name, err)
+					close(failed)
+				})
+			}
+		}(hook)
+	}
+
+	select {
+	case <-stopCtx.Done():
+		logger.Info("shutting down", slog.Any("cause", context.Cause(stopCtx)))
+	case <-failed:
+		logger.Error("component failed - shutting down", slog.Any("err", startErr))
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.WithoutCancel(ctx), r.opts.shutdownTimeout)
+	defer cancelShutdown()
+	stopErr := r.stop(shutdownCtx, logger)
+
+	cancelStart()
+	started := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(started)
+	}()
+	select {
+	case <-started:
+	case <-shutdownCtx.Done():
+		logger.Warn("some components are still running after shutdown timeout")
+	}
+
+	// synchronizes with a hook that may be failing right now, so startErr is safe to read
+	errOnce.Do(func() {})
+	return errors.Join(startErr, stopErr)
+}
+
+func (r *Runner) stop(ctx context.Context, logger *slog.Logger) error {
+	var errs []error
+	for i := len(r.hooks) - 1; i >= 0; i-- {
+		hook := r.hooks[i]
+		if hook.Stop == nil {
+			continue
+		}
+		logger.Info("stopping component", slog.String("component", hook.Name))
+		startTime := time.Now()
+		if err := hook.Stop(ctx); err != nil {
+			logger.Error("failed stop component", slog.String("component", hook.Name), slog.Any("err", err))
+			errs = append(errs, fmt.Errorf("failed stop %s: %w", hook.Name, err))
+			continue
+		}
+		logger.Info("component stopped", slog.String("component", hook.Name),
+			slog.Int64("duration_ms", time.Since(startTime).Milliseconds()))
+	}
+	return errors.Join(errs...)
+}
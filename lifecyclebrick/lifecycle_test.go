@@ -0,0 +1,110 @@
+package lifecyclebrick
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	events []string
+	mu     sync.Mutex
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// blockingHook imitates a server: Start blocks until Stop is called.
+func blockingHook(name string, rec *recorder) Hook {
+	stopped := make(chan struct{})
+	return Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			<-stopped
+			return nil
+		},
+		Stop: func(context.Context) error {
+			rec.add("stop " + name)
+			close(stopped)
+			return nil
+		},
+	}
+}
+
+func TestRunner_Run_StopsInReverseOrderOnCtxDone(t *testing.T) {
+	rec := &recorder{}
+	r := New()
+	r.Append(
+		ShutdownHook("otel", func(context.Context) error {
+			rec.add("stop otel")
+			return nil
+		}),
+		blockingHook("grpc", rec),
+		blockingHook("http", rec),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("runner did not stop")
+	}
+	assert.Equal(t, []string{"stop http", "stop grpc", "stop otel"}, rec.events)
+}
+
+func TestRunner_Run_StopsAllOnStartErr(t *testing.T) {
+	startErr := errors.New("listen failed")
+	stopErr := errors.New("close failed")
+	rec := &recorder{}
+	r := New()
+	r.Append(
+		Hook{
+			Name: "db",
+			Stop: func(context.Context) error {
+				rec.add("stop db")
+				return stopErr
+			},
+		},
+		blockingHook("http", rec),
+		Hook{
+			Name: "grpc",
+			Start: func(context.Context) error {
+				return startErr
+			},
+		},
+	)
+
+	err := r.Run(context.Background())
+	assert.ErrorIs(t, err, startErr)
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, []string{"stop http", "stop db"}, rec.events)
+}
+
+func TestRunner_Run_ShutdownTimeout(t *testing.T) {
+	r := New(WithShutdownTimeout(50 * time.Millisecond))
+	r.Append(Hook{
+		Name: "stuck",
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,64 @@
+package otelbrick
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+// BaggageAttrs returns the W3C baggage members from the context as attributes.
+// Only the members with keys from the allow-list are returned to bound the attributes cardinality.
+// The values are still controlled by the client that sent the baggage, so allow only the keys
+// with bounded values or the ones set by the trusted edge (see jwtbrick.ClaimsToBaggage).
+func BaggageAttrs(ctx context.Context, allowList ...string) []attribute.KeyValue {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 || len(allowList) == 0 {
+		return nil
+	}
+	attrs := make([]attribute.KeyValue, 0, len(allowList))
+	for _, m := range bag.Members() {
+		if slices.Contains(allowList, m.Key()) {
+			attrs = append(attrs, attribute.String(m.Key(), m.Value()))
+		}
+	}
+	return attrs
+}
+
+// BaggageToCtx promotes the W3C baggage members with keys from the allow-list
+// to the context attributes (see AttrsToCtx) and to the logger in the context (see slogbrick.FromCtx),
+// so the values set at the edge (e.g. tenant ID) get to the logs and metrics of the downstream services.
+func BaggageToCtx(ctx context.Context, allowList ...string) context.Context {
+	attrs := BaggageAttrs(ctx, allowList...)
+	if len(attrs) == 0 {
+		return ctx
+	}
+	logAttrs := make([]interface{}, 0, len(attrs))
+	for _, attr := range attrs {
+		logAttrs = append(logAttrs, slog.String(string(attr.Key), attr.Value.AsString()))
+	}
+	ctx = slogbrick.ToCtx(ctx, slogbrick.FromCtx(ctx).With(logAttrs...))
+	return AttrsToCtx(ctx, attrs)
+}
+
+// SetBaggage sets the members to the W3C baggage in the context.
+// The existing members with the same keys are replaced. The values are percent-encoded.
+func SetBaggage(ctx context.Context, members map[string]string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+	for key, value := range members {
+		m, err := baggage.NewMember(key, url.PathEscape(value))
+		if err != nil {
+			return ctx, fmt.Errorf("failed create baggage member %s: %w", key, err)
+		}
+		if bag, err = bag.SetMember(m); err != nil {
+			return ctx, fmt.Errorf("failed set baggage member %s: %w", key, err)
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
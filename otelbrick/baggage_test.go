@@ -0,0 +1,36 @@
+package otelbrick
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/demeero/bricks/slogbrick"
+)
+
+func TestBaggageToCtx(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := slogbrick.ToCtx(context.Background(), slog.New(slog.NewTextHandler(buf, nil)))
+	ctx, err := SetBaggage(ctx, map[string]string{"tenant.id": "acme corp", "user.email": "a@b.c"})
+	require.NoError(t, err)
+	assert.Equal(t, "acme corp", baggage.FromContext(ctx).Member("tenant.id").Value())
+
+	ctx = BaggageToCtx(ctx, "tenant.id", "client.app")
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tenant.id", "acme corp")}, AttrsFromCtx(ctx))
+	slogbrick.FromCtx(ctx).Info("test")
+	assert.Contains(t, buf.String(), `tenant.id="acme corp"`)
+	assert.NotContains(t, buf.String(), "user.email")
+
+	assert.Equal(t, context.Background(), BaggageToCtx(context.Background(), "tenant.id"))
+}
+
+func TestSetBaggage_InvalidKey(t *testing.T) {
+	_, err := SetBaggage(context.Background(), map[string]string{"tenant id": "acme"})
+	assert.Error(t, err)
+}
@@ -2,11 +2,15 @@ package otelbrick
 
 import (
 	"context"
+	"slices"
 
 	"go.opentelemetry.io/otel/attribute"
 )
 
-type attrsCtxKey struct{}
+type (
+	attrsCtxKey            struct{}
+	metricExcludedAttrsKey struct{}
+)
 
 var attrsKey = attrsCtxKey{}
 
@@ -27,6 +31,31 @@ func AttrsFromCtx(ctx context.Context) []attribute.KeyValue {
 	return attrs
 }
 
+// ExcludeFromMetricsToCtx marks the attribute keys in the context to be excluded from MetricAttrsFromCtx,
+// e.g. the request ID that is unique per request and would make the metrics cardinality unbounded.
+func ExcludeFromMetricsToCtx(ctx context.Context, keys ...attribute.Key) context.Context {
+	existingKeys, _ := ctx.Value(metricExcludedAttrsKey{}).([]attribute.Key)
+	keys = append(existingKeys[:len(existingKeys):len(existingKeys)], keys...)
+	return context.WithValue(ctx, metricExcludedAttrsKey{}, keys)
+}
+
+// MetricAttrsFromCtx returns the attributes from the context except the ones excluded by ExcludeFromMetricsToCtx.
+// Appending to the returned slice doesn't modify the attributes shared via the context.
+func MetricAttrsFromCtx(ctx context.Context) []attribute.KeyValue {
+	attrs := AttrsFromCtx(ctx)
+	excludedKeys, _ := ctx.Value(metricExcludedAttrsKey{}).([]attribute.Key)
+	if len(excludedKeys) == 0 {
+		return attrs[:len(attrs):len(attrs)]
+	}
+	metricAttrs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if !slices.Contains(excludedKeys, attr.Key) {
+			metricAttrs = append(metricAttrs, attr)
+		}
+	}
+	return metricAttrs
+}
+
 // FilterAttrsFromCtx returns the attributes from the context that match the provided list of attribute names.
 func FilterAttrsFromCtx(ctx context.Context, attrs []string) []attribute.KeyValue {
 	attrsFromCtx := AttrsFromCtx(ctx)
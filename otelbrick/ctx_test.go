@@ -93,3 +93,16 @@ func TestFilterAttrsFromCtx(t *testing.T) {
 	assert.Equal(t, expectedKey, filteredAttrs[0].Key)
 	assert.Equal(t, expectedValue, filteredAttrs[0].Value)
 }
+
+func TestMetricAttrsFromCtx(t *testing.T) {
+	ctx := AttrsToCtx(context.Background(), []attribute.KeyValue{
+		attribute.String("tenant", "acme"),
+		attribute.String("request.id", "req-1"),
+	})
+	assert.Equal(t, AttrsFromCtx(ctx), MetricAttrsFromCtx(ctx))
+	assert.Len(t, MetricAttrsFromCtx(ctx), cap(MetricAttrsFromCtx(ctx)))
+
+	ctx = ExcludeFromMetricsToCtx(ctx, "request.id")
+	assert.Len(t, AttrsFromCtx(ctx), 2)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tenant", "acme")}, MetricAttrsFromCtx(ctx))
+}
@@ -0,0 +1,193 @@
+package otelbrick
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorSpanProcessorOption is a function that configures ErrorSpanProcessor.
+type ErrorSpanProcessorOption func(*errorSpanProcessorOpts)
+
+type errorSpanProcessorOpts struct {
+	MaxTraces      int
+	MaxSpans       int
+	MaxEndedTraces int
+	TTL            time.Duration
+}
+
+// WithErrorBufferMaxTraces sets the max number of the traces buffered at once, the spans of the other traces are dropped.
+// 1000 is used by default.
+func WithErrorBufferMaxTraces(n int) ErrorSpanProcessorOption {
+	return func(opts *errorSpanProcessorOpts) {
+		opts.MaxTraces = n
+	}
+}
+
+// WithErrorBufferMaxSpans sets the max number of the spans buffered per trace.
+// 512 is used by default.
+func WithErrorBufferMaxSpans(n int) ErrorSpanProcessorOption {
+	return func(opts *errorSpanProcessorOpts) {
+		opts.MaxSpans = n
+	}
+}
+
+// WithErrorBufferMaxEndedTraces sets the max number of the ended traces remembered to handle their late spans,
+// the oldest ones are forgotten first. 10000 is used by default.
+func WithErrorBufferMaxEndedTraces(n int) ErrorSpanProcessorOption {
+	return func(opts *errorSpanProcessorOpts) {
+		opts.MaxEndedTraces = n
+	}
+}
+
+// WithErrorBufferTTL sets how long the trace is buffered if its local root span doesn't end
+// and how long the ended trace is remembered to handle its late spans.
+// 1m is used by default.
+func WithErrorBufferTTL(ttl time.Duration) ErrorSpanProcessorOption {
+	return func(opts *errorSpanProcessorOpts) {
+		opts.TTL = ttl
+	}
+}
+
+type bufferedTrace struct {
+	started time.Time
+	spans   []sdktrace.ReadOnlySpan
+	errored bool
+}
+
+type endedTrace struct {
+	expiresAt time.Time
+	traceID   trace.TraceID
+}
+
+// ErrorSpanProcessor is a tail-style span processor that always samples the traces with errors.
+// The sampled spans are passed to the next processor as is.
+// The recorded but not sampled spans (see RecordOnlySampler) are buffered per trace locally:
+// if a span of the trace ends with the error status, the buffered and the following spans of the trace
+// are passed to the next processor as sampled, otherwise they are dropped when the local root span ends.
+// The ended traces are remembered for the TTL: the spans ending after the local root span (e.g. async work)
+// are passed as sampled if the trace has errors and dropped otherwise, unless they end with the error status themselves.
+// Only the spans of this process are exported, the spans of the other services in the trace stay unsampled.
+type ErrorSpanProcessor struct {
+	sdktrace.SpanProcessor
+	now    func() time.Time
+	traces map[trace.TraceID]*bufferedTrace
+	// ended maps the ended traces to whether they have errors, endedQueue orders them by expiration.
+	ended      map[trace.TraceID]bool
+	endedQueue []endedTrace
+	lastSweep  time.Time
+	opts       errorSpanProcessorOpts
+	mu         sync.Mutex
+}
+
+// NewErrorSpanProcessor creates a new ErrorSpanProcessor that passes the spans to next.
+func NewErrorSpanProcessor(next sdktrace.SpanProcessor, options ...ErrorSpanProcessorOption) *ErrorSpanProcessor {
+	opts := errorSpanProcessorOpts{MaxTraces: 1000, MaxSpans: 512, MaxEndedTraces: 10000, TTL: time.Minute}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &ErrorSpanProcessor{
+		SpanProcessor: next,
+		now:           time.Now,
+		traces:        make(map[trace.TraceID]*bufferedTrace),
+		ended:         make(map[trace.TraceID]bool),
+		opts:          opts,
+	}
+}
+
+// OnEnd implements sdktrace.SpanProcessor.
+func (sp *ErrorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		sp.SpanProcessor.OnEnd(s)
+		return
+	}
+	for _, span := range sp.buffer(s) {
+		sp.SpanProcessor.OnEnd(sampledSpan{ReadOnlySpan: span})
+	}
+}
+
+// buffer buffers the span and returns the spans to pass to the next processor.
+func (sp *ErrorSpanProcessor) buffer(s sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	now := sp.now()
+	sp.sweep(now)
+
+	traceID := s.SpanContext().TraceID()
+	if errored, ok := sp.ended[traceID]; ok {
+		// the late span of the ended trace: its buffered spans are already passed or dropped
+		if !errored && s.Status().Code != codes.Error {
+			return nil
+		}
+		sp.ended[traceID] = true
+		return []sdktrace.ReadOnlySpan{s}
+	}
+
+	bt, ok := sp.traces[traceID]
+	if !ok && len(sp.traces) < sp.opts.MaxTraces {
+		bt = &bufferedTrace{started: now}
+		sp.traces[traceID] = bt
+	}
+	var flush []sdktrace.ReadOnlySpan
+	switch {
+	case bt == nil:
+		// too many traces are buffered, so the spans of this one are dropped
+	case bt.errored:
+		flush = []sdktrace.ReadOnlySpan{s}
+	case s.Status().Code == codes.Error:
+		bt.errored = true
+		flush = append(bt.spans, s)
+		bt.spans = nil
+	case len(bt.spans) < sp.opts.MaxSpans:
+		bt.spans = append(bt.spans, s)
+	}
+	if parent := s.Parent(); !parent.IsValid() || parent.IsRemote() {
+		// the local root span usually ends last, the rest of the spans are handled as late ones
+		delete(sp.traces, traceID)
+		sp.markEnded(traceID, bt != nil && bt.errored, now)
+	}
+	return flush
+}
+
+// markEnded remembers the ended trace for the TTL, forgetting the oldest one if there are too many.
+func (sp *ErrorSpanProcessor) markEnded(traceID trace.TraceID, errored bool, now time.Time) {
+	if sp.opts.MaxEndedTraces < 1 {
+		return
+	}
+	if len(sp.endedQueue) >= sp.opts.MaxEndedTraces {
+		delete(sp.ended, sp.endedQueue[0].traceID)
+		sp.endedQueue = sp.endedQueue[1:]
+	}
+	sp.ended[traceID] = errored
+	sp.endedQueue = append(sp.endedQueue, endedTrace{traceID: traceID, expiresAt: now.Add(sp.opts.TTL)})
+}
+
+// sweep removes the expired ended traces and the expired buffered traces (not more often than once a TTL).
+func (sp *ErrorSpanProcessor) sweep(now time.Time) {
+	for len(sp.endedQueue) > 0 && now.After(sp.endedQueue[0].expiresAt) {
+		delete(sp.ended, sp.endedQueue[0].traceID)
+		sp.endedQueue = sp.endedQueue[1:]
+	}
+	if now.Sub(sp.lastSweep) < sp.opts.TTL {
+		return
+	}
+	sp.lastSweep = now
+	for traceID, bt := range sp.traces {
+		if now.Sub(bt.started) > sp.opts.TTL {
+			delete(sp.traces, traceID)
+		}
+	}
+}
+
+// sampledSpan marks the recorded span as sampled, so the exporting processors don't drop it.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	spanCtx := s.ReadOnlySpan.SpanContext()
+	return spanCtx.WithTraceFlags(spanCtx.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,116 @@
+package otelbrick
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestErrorSpanProcessor(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(RecordOnlySampler(DebugSampler(sdktrace.NeverSample()))),
+		sdktrace.WithSpanProcessor(NewErrorSpanProcessor(sr)))
+	tracer := tp.Tracer("test")
+
+	// the trace without errors is dropped
+	ctx, root := tracer.Start(context.Background(), "ok-root")
+	_, child := tracer.Start(ctx, "ok-child")
+	child.End()
+	root.End()
+	assert.Empty(t, sr.Ended())
+
+	// the trace with an error is exported: the buffered spans and the following ones
+	ctx, root = tracer.Start(context.Background(), "err-root")
+	_, first := tracer.Start(ctx, "first")
+	first.End()
+	_, failed := tracer.Start(ctx, "failed")
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+	root.End()
+
+	ended := sr.Ended()
+	names := make([]string, 0, len(ended))
+	for _, s := range ended {
+		assert.True(t, s.SpanContext().IsSampled())
+		names = append(names, s.Name())
+	}
+	assert.Equal(t, []string{"first", "failed", "err-root"}, names)
+
+	// the sampled spans are passed as is
+	_, forced := tracer.Start(ForceSampleToCtx(context.Background()), "forced")
+	forced.End()
+	assert.Len(t, sr.Ended(), 4)
+}
+
+func TestErrorSpanProcessor_MaxTraces(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(RecordOnlySampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(NewErrorSpanProcessor(sr, WithErrorBufferMaxTraces(1))))
+	tracer := tp.Tracer("test")
+
+	ctx1, root1 := tracer.Start(context.Background(), "root1")
+	_, child1 := tracer.Start(ctx1, "child1")
+	child1.End()
+	ctx2, root2 := tracer.Start(context.Background(), "root2")
+	_, child2 := tracer.Start(ctx2, "child2")
+	child2.SetStatus(codes.Error, "boom")
+	child2.End()
+	root2.End()
+	root1.End()
+
+	// the second trace isn't buffered, so its error span isn't exported
+	assert.Empty(t, sr.Ended())
+}
+
+func TestErrorSpanProcessor_LateSpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	processor := NewErrorSpanProcessor(sr)
+	processor.now = func() time.Time { return now }
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(RecordOnlySampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(processor))
+	tracer := tp.Tracer("test")
+
+	// the late span of the trace without errors is dropped and doesn't buffer the trace again
+	ctx, root := tracer.Start(context.Background(), "ok-root")
+	_, late := tracer.Start(ctx, "ok-late")
+	root.End()
+	late.End()
+	assert.Empty(t, sr.Ended())
+	assert.Empty(t, processor.traces)
+
+	// the late error span is exported
+	_, lateErr := tracer.Start(ctx, "ok-late-err")
+	lateErr.SetStatus(codes.Error, "boom")
+	lateErr.End()
+	require.Len(t, sr.Ended(), 1)
+	assert.Equal(t, "ok-late-err", sr.Ended()[0].Name())
+
+	// the late span of the trace with errors is exported
+	ctx, root = tracer.Start(context.Background(), "err-root")
+	_, late = tracer.Start(ctx, "err-late")
+	root.SetStatus(codes.Error, "boom")
+	root.End()
+	late.End()
+	ended := sr.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, "err-root", ended[1].Name())
+	assert.Equal(t, "err-late", ended[2].Name())
+	assert.True(t, ended[2].SpanContext().IsSampled())
+
+	// the ended traces are forgotten after the TTL
+	now = now.Add(2 * time.Minute)
+	_, late = tracer.Start(ctx, "expired-late")
+	late.End()
+	assert.Empty(t, processor.ended)
+	assert.Len(t, sr.Ended(), 3)
+}
@@ -0,0 +1,64 @@
+package otelbrick
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagator names as in OTEL_PROPAGATORS of the OpenTelemetry SDK environment variables specification.
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorBaggage      = "baggage"
+	PropagatorB3           = "b3"
+	PropagatorB3Multi      = "b3multi"
+	PropagatorJaeger       = "jaeger"
+	PropagatorNone         = "none"
+)
+
+// NewPropagator returns a composite propagator of the propagators with the names
+// (tracecontext, baggage, b3 - single header, b3multi - multiple headers, jaeger, none).
+// The names are case-insensitive, the surrounding spaces and empty names are ignored.
+// The propagators inject in the order of the names and extract in the same order,
+// so the later ones override the context extracted by the former.
+// If names are empty, tracecontext and baggage are used.
+// "none" disables the propagation, so it can't be combined with other names.
+func NewPropagator(names ...string) (propagation.TextMapPropagator, error) {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) == 0 {
+		normalized = []string{PropagatorTraceContext, PropagatorBaggage}
+	}
+	if slices.Contains(normalized, PropagatorNone) {
+		if len(normalized) > 1 {
+			return nil, fmt.Errorf("propagator %s can't be combined with others: %v", PropagatorNone, normalized)
+		}
+		return propagation.NewCompositeTextMapPropagator(), nil
+	}
+	propagators := make([]propagation.TextMapPropagator, 0, len(normalized))
+	for _, name := range normalized {
+		switch name {
+		case PropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case PropagatorB3:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			propagators = append(propagators, jaeger.Jaeger{})
+		default:
+			return nil, fmt.Errorf("unknown propagator: %s", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
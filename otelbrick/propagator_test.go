@@ -0,0 +1,43 @@
+package otelbrick
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropagator(t *testing.T) {
+	tests := []struct {
+		name       string
+		names      []string
+		wantFields []string
+		wantErr    bool
+	}{
+		{name: "default", wantFields: []string{"traceparent", "tracestate", "baggage"}},
+		{name: "b3", names: []string{PropagatorB3}, wantFields: []string{"b3"}},
+		{name: "b3multi", names: []string{PropagatorB3Multi}, wantFields: []string{"x-b3-traceid", "x-b3-spanid", "x-b3-sampled", "x-b3-flags"}},
+		{name: "jaeger", names: []string{PropagatorJaeger}, wantFields: []string{"uber-trace-id"}},
+		{name: "none", names: []string{PropagatorNone}},
+		{name: "none with others", names: []string{PropagatorTraceContext, PropagatorNone}, wantErr: true},
+		{name: "spaces and case", names: []string{" TraceContext", "", "B3 "}, wantFields: []string{"traceparent", "b3"}},
+		{name: "empty names", names: []string{"", " "}, wantFields: []string{"traceparent", "baggage"}},
+		{name: "unknown", names: []string{"xray"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPropagator(tt.names...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, field := range tt.wantFields {
+				assert.Contains(t, p.Fields(), field)
+			}
+			if len(tt.wantFields) == 0 {
+				assert.Empty(t, p.Fields())
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package otelbrick
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingRule sets the sampling rate for the spans which name or http.route attribute matches the pattern.
+type SamplingRule struct {
+	Pattern *regexp.Regexp
+	Rate    float64
+}
+
+// ParseSamplingRules parses the semicolon-separated rules in "pattern=rate" format,
+// e.g. "^GET /health=0;/orders/[0-9]{1,3}=0.5". The semicolon separates the rules as the comma may appear in the patterns.
+// The surrounding spaces and empty rules are ignored.
+func ParseSamplingRules(rules string) ([]SamplingRule, error) {
+	parsed := make([]SamplingRule, 0, strings.Count(rules, ";")+1)
+	for _, rule := range strings.Split(rules, ";") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndex(rule, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid sampling rule %q: expected pattern=rate", rule)
+		}
+		pattern, err := regexp.Compile(rule[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling rule %q pattern: %w", rule, err)
+		}
+		rate, err := strconv.ParseFloat(rule[i+1:], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling rule %q rate: %w", rule, err)
+		}
+		parsed = append(parsed, SamplingRule{Pattern: pattern, Rate: rate})
+	}
+	return parsed, nil
+}
+
+type ruleSampler struct {
+	fallback sdktrace.Sampler
+	rules    []SamplingRule
+	samplers []sdktrace.Sampler
+}
+
+// RuleSampler returns a sampler that samples the spans by the rate of the first matching rule
+// and delegates to the fallback if no rule matches.
+// The rules are matched against the span name and the http.route attribute.
+func RuleSampler(rules []SamplingRule, fallback sdktrace.Sampler) sdktrace.Sampler {
+	samplers := make([]sdktrace.Sampler, 0, len(rules))
+	for _, rule := range rules {
+		samplers = append(samplers, sdktrace.TraceIDRatioBased(rule.Rate))
+	}
+	return &ruleSampler{rules: rules, samplers: samplers, fallback: fallback}
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	var route string
+	for _, attr := range p.Attributes {
+		if attr.Key == semconv.HTTPRouteKey {
+			route = attr.Value.AsString()
+			break
+		}
+	}
+	for i, rule := range s.rules {
+		if rule.Pattern.MatchString(p.Name) || (route != "" && rule.Pattern.MatchString(route)) {
+			return s.samplers[i].ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+type rateLimitingSampler struct {
+	next    sdktrace.Sampler
+	now     func() time.Time
+	updated time.Time
+	perSec  float64
+	tokens  float64
+	mu      sync.Mutex
+}
+
+// RateLimitingSampler returns a sampler that samples not more than perSec of the traces sampled by next.
+// The limit is applied with a token bucket with the burst of max(1, perSec).
+func RateLimitingSampler(perSec float64, next sdktrace.Sampler) sdktrace.Sampler {
+	return &rateLimitingSampler{next: next, perSec: perSec, tokens: math.Max(1, perSec), now: time.Now}
+}
+
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.next.ShouldSample(p)
+	if res.Decision != sdktrace.RecordAndSample || s.take() {
+		return res
+	}
+	return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: res.Tracestate}
+}
+
+func (s *rateLimitingSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if !s.updated.IsZero() {
+		s.tokens = math.Min(math.Max(1, s.perSec), s.tokens+now.Sub(s.updated).Seconds()*s.perSec)
+	}
+	s.updated = now
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g,%s}", s.perSec, s.next.Description())
+}
+
+type forceSampleCtxKey struct{}
+
+// ForceSampleToCtx marks the context to sample the spans started with it regardless of the other samplers
+// (see DebugSampler and httpbrick.WithTraceDebugHeader).
+func ForceSampleToCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleCtxKey{}, true)
+}
+
+// ForceSampleFromCtx reports whether the context is marked by ForceSampleToCtx.
+func ForceSampleFromCtx(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceSampleCtxKey{}).(bool)
+	return forced
+}
+
+type debugSampler struct {
+	next sdktrace.Sampler
+}
+
+// DebugSampler returns a sampler that samples the spans started with the context marked by ForceSampleToCtx
+// and delegates to next otherwise.
+func DebugSampler(next sdktrace.Sampler) sdktrace.Sampler {
+	return &debugSampler{next: next}
+}
+
+func (s *debugSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if ForceSampleFromCtx(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.next.ShouldSample(p)
+}
+
+func (s *debugSampler) Description() string {
+	return fmt.Sprintf("DebugSampler{%s}", s.next.Description())
+}
+
+type recordOnlySampler struct {
+	next sdktrace.Sampler
+}
+
+// RecordOnlySampler returns a sampler that records the spans dropped by next without sampling them,
+// so ErrorSpanProcessor can export the traces with errors.
+func RecordOnlySampler(next sdktrace.Sampler) sdktrace.Sampler {
+	return &recordOnlySampler{next: next}
+}
+
+func (s *recordOnlySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.next.ShouldSample(p)
+	if res.Decision == sdktrace.Drop {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+func (s *recordOnlySampler) Description() string {
+	return fmt.Sprintf("RecordOnlySampler{%s}", s.next.Description())
+}
@@ -0,0 +1,90 @@
+package otelbrick
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseSamplingRules(t *testing.T) {
+	rules, err := ParseSamplingRules(" ^GET /health=0; a=b=0.5;;/orders/[0-9]{1,3}=1 ")
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "^GET /health", rules[0].Pattern.String())
+	assert.Equal(t, 0.0, rules[0].Rate)
+	assert.Equal(t, "a=b", rules[1].Pattern.String())
+	assert.Equal(t, 0.5, rules[1].Rate)
+	assert.Equal(t, "/orders/[0-9]{1,3}", rules[2].Pattern.String())
+	assert.Equal(t, 1.0, rules[2].Rate)
+
+	rules, err = ParseSamplingRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{"no-rate", "[=0.1", "/x=high"} {
+		_, err = ParseSamplingRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRuleSampler(t *testing.T) {
+	sampler := RuleSampler([]SamplingRule{
+		{Pattern: regexp.MustCompile(`^/health$`), Rate: 0},
+		{Pattern: regexp.MustCompile(`^GET /orders`), Rate: 1},
+	}, sdktrace.NeverSample())
+
+	tests := []struct {
+		name   string
+		params sdktrace.SamplingParameters
+		want   sdktrace.SamplingDecision
+	}{
+		{
+			name:   "route rule",
+			params: sdktrace.SamplingParameters{Name: "GET /health", Attributes: []attribute.KeyValue{semconv.HTTPRoute("/health")}},
+			want:   sdktrace.Drop,
+		},
+		{name: "span name rule", params: sdktrace.SamplingParameters{Name: "GET /orders/:id"}, want: sdktrace.RecordAndSample},
+		{name: "fallback", params: sdktrace.SamplingParameters{Name: "GET /users"}, want: sdktrace.Drop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.ParentContext = context.Background()
+			tt.params.TraceID = trace.TraceID{1}
+			assert.Equal(t, tt.want, sampler.ShouldSample(tt.params).Decision)
+		})
+	}
+}
+
+func TestRateLimitingSampler(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := RateLimitingSampler(2, sdktrace.AlwaysSample())
+	sampler.(*rateLimitingSampler).now = func() time.Time { return now }
+	params := sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}}
+
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.Drop, sampler.ShouldSample(params).Decision)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+	assert.Equal(t, sdktrace.Drop, sampler.ShouldSample(params).Decision)
+
+	assert.Equal(t, sdktrace.Drop, RateLimitingSampler(2, sdktrace.NeverSample()).ShouldSample(params).Decision)
+}
+
+func TestDebugSampler(t *testing.T) {
+	sampler := RecordOnlySampler(DebugSampler(sdktrace.NeverSample()))
+	params := sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}}
+	assert.Equal(t, sdktrace.RecordOnly, sampler.ShouldSample(params).Decision)
+
+	params.ParentContext = ForceSampleToCtx(context.Background())
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+}
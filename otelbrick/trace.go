@@ -11,7 +11,6 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -19,8 +18,13 @@ import (
 )
 
 type TraceConfig struct {
-	SpanExclusions        map[attribute.Key]*regexp.Regexp
-	Headers               map[string]string
+	SpanExclusions map[attribute.Key]*regexp.Regexp
+	Headers        map[string]string
+	// Propagators are the names of the propagators to set globally (see NewPropagator).
+	// They are set even if the tracing is disabled, so the trace context and baggage are passed through.
+	Propagators []string
+	// SamplingRules set the sampling rates per span name or route (see RuleSampler), SamplingRate is the fallback.
+	SamplingRules         []SamplingRule
 	ServiceName           string
 	ServiceNamespace      string
 	DeploymentEnvironment string
@@ -28,10 +32,20 @@ type TraceConfig struct {
 	OTELHTTPEndpoint      string
 	OTELHTTPPathPrefix    string
 	SamplingRate          float64
-	Insecure              bool
+	// SamplingRateLimit limits the sampled traces per second (see RateLimitingSampler). 0 disables the limit.
+	SamplingRateLimit float64
+	Insecure          bool
+	// SampleOnError exports the traces with errors regardless of the sampling (see ErrorSpanProcessor).
+	SampleOnError bool
 }
 
 func InitTrace(ctx context.Context, cfg TraceConfig, opts ...sdktrace.TracerProviderOption) (func(context.Context) error, error) {
+	propagator, err := NewPropagator(cfg.Propagators...)
+	if err != nil {
+		return nil, fmt.Errorf("failed create propagator: %w", err)
+	}
+	otel.SetTextMapPropagator(propagator)
+
 	if cfg.OTELHTTPEndpoint == "" && cfg.OTELGRPCEndpoint == "" {
 		slog.Info("otel trace disabled")
 		otel.SetTracerProvider(nooptrace.NewTracerProvider())
@@ -48,24 +62,43 @@ func InitTrace(ctx context.Context, cfg TraceConfig, opts ...sdktrace.TracerProv
 		slog.Info("span exclusions enabled")
 		spanProcessor = newExclusionSpanProcessor(spanProcessor, cfg.SpanExclusions)
 	}
-	sampler := sdktrace.AlwaysSample()
-	if cfg.SamplingRate > 0 {
-		slog.Info("span sampling enabled")
-		sampler = sdktrace.TraceIDRatioBased(cfg.SamplingRate)
+	sampler := createSampler(cfg)
+	if cfg.SampleOnError {
+		slog.Info("sampling on error enabled")
+		sampler = RecordOnlySampler(sampler)
+		spanProcessor = NewErrorSpanProcessor(spanProcessor)
 	}
 	opts = append([]sdktrace.TracerProviderOption{
-		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(createRes(cfg)),
 		sdktrace.WithSpanProcessor(spanProcessor),
 	}, opts...)
 	tracerProvider := sdktrace.NewTracerProvider(opts...)
 	otel.SetTracerProvider(tracerProvider)
 
-	otel.SetTextMapPropagator(propagation.TraceContext{})
-
 	return tracerProvider.Shutdown, nil
 }
 
+// createSampler builds the sampler from the config:
+// the spans of the context marked by ForceSampleToCtx are always sampled,
+// the other spans follow the parent decision, and the root spans are sampled by the rules, the rate and the rate limit.
+func createSampler(cfg TraceConfig) sdktrace.Sampler {
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SamplingRate > 0 {
+		slog.Info("span sampling enabled")
+		sampler = sdktrace.TraceIDRatioBased(cfg.SamplingRate)
+	}
+	if len(cfg.SamplingRules) > 0 {
+		slog.Info("span sampling rules enabled", slog.Int("rules", len(cfg.SamplingRules)))
+		sampler = RuleSampler(cfg.SamplingRules, sampler)
+	}
+	if cfg.SamplingRateLimit > 0 {
+		slog.Info("span sampling rate limit enabled", slog.Float64("per_sec", cfg.SamplingRateLimit))
+		sampler = RateLimitingSampler(cfg.SamplingRateLimit, sampler)
+	}
+	return DebugSampler(sdktrace.ParentBased(sampler))
+}
+
 func createRes(cfg TraceConfig) *resource.Resource {
 	return resource.NewWithAttributes(
 		semconv.SchemaURL,
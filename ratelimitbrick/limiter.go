@@ -0,0 +1,173 @@
+// Package ratelimitbrick provides rate limiters to protect the services from abusive clients.
+package ratelimitbrick
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+// Result is a result of the rate limit check.
+type Result struct {
+	// Limit is the max number of requests in the period (the burst for token bucket).
+	Limit int
+	// Remaining is the number of requests left in the period.
+	Remaining int
+	// Reset is the time until the limit is fully restored.
+	Reset time.Duration
+	// RetryAfter is the time to wait before the next request is allowed. It's zero for allowed requests.
+	RetryAfter time.Duration
+	// Allowed reports whether the request is allowed.
+	Allowed bool
+}
+
+// Err returns the error wrapping errbrick.ErrRateLimited with the retry hint for the rejected request, nil otherwise.
+func (r Result) Err() error {
+	if r.Allowed {
+		return nil
+	}
+	return errbrick.NewDetailed(errbrick.ErrRateLimited, "too many requests", errbrick.WithRetryAfter(r.RetryAfter))
+}
+
+// Limiter checks if the request identified by the key is allowed.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// TokenBucket is a token bucket limiter: the bucket of burst size is refilled at the rate per second,
+// each request takes a token. It allows short bursts while keeping the average rate.
+type TokenBucket struct {
+	store Store
+	now   func() time.Time
+	rate  float64
+	burst int
+}
+
+// NewTokenBucket creates a new TokenBucket.
+func NewTokenBucket(store Store, rate float64, burst int) *TokenBucket {
+	return &TokenBucket{store: store, rate: rate, burst: burst, now: time.Now}
+}
+
+// Allow implements Limiter.
+func (l *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.now()
+	fillTime := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+	var allowed bool
+	state, err := l.store.Update(ctx, key, fillTime, func(s State) State {
+		if s.Updated.IsZero() {
+			s.Tokens = float64(l.burst)
+		} else {
+			s.Tokens = math.Min(float64(l.burst), s.Tokens+now.Sub(s.Updated).Seconds()*l.rate)
+		}
+		s.Updated = now
+		allowed = s.Tokens >= 1
+		if allowed {
+			s.Tokens--
+		}
+		return s
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed update token bucket state: %w", err)
+	}
+
+	res := Result{
+		Limit:     l.burst,
+		Remaining: int(state.Tokens),
+		Reset:     time.Duration((float64(l.burst) - state.Tokens) / l.rate * float64(time.Second)),
+		Allowed:   allowed,
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - state.Tokens) / l.rate * float64(time.Second))
+	}
+	return res, nil
+}
+
+// SlidingWindow is a sliding window limiter that allows limit requests per window.
+// It approximates the sliding window by weighting the previous fixed window count by its overlap with the sliding one,
+// so it needs O(1) state per key.
+type SlidingWindow struct {
+	store  Store
+	now    func() time.Time
+	window time.Duration
+	limit  int
+}
+
+// NewSlidingWindow creates a new SlidingWindow.
+func NewSlidingWindow(store Store, limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{store: store, limit: limit, window: window, now: time.Now}
+}
+
+// Allow implements Limiter.
+func (l *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.now()
+	windowStart := now.Truncate(l.window)
+	var (
+		allowed bool
+		used    float64
+	)
+	state, err := l.store.Update(ctx, key, 2*l.window, func(s State) State {
+		switch {
+		case s.WindowStart.Equal(windowStart):
+		case s.WindowStart.Equal(windowStart.Add(-l.window)):
+			s.PrevCount, s.Count = s.Count, 0
+		default:
+			s.PrevCount, s.Count = 0, 0
+		}
+		s.WindowStart = windowStart
+		used = l.weighted(s, now)
+		allowed = used+1 <= float64(l.limit)
+		if allowed {
+			s.Count++
+			used++
+		}
+		return s
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed update sliding window state: %w", err)
+	}
+
+	res := Result{
+		Limit:     l.limit,
+		Remaining: max(0, l.limit-int(math.Ceil(used))),
+		Reset:     state.WindowStart.Add(l.window).Sub(now),
+		Allowed:   allowed,
+	}
+	if !allowed {
+		res.RetryAfter = l.retryAfter(state, now)
+	}
+	return res, nil
+}
+
+func (l *SlidingWindow) weighted(s State, now time.Time) float64 {
+	elapsed := now.Sub(s.WindowStart).Seconds() / l.window.Seconds()
+	return float64(s.PrevCount)*(1-elapsed) + float64(s.Count)
+}
+
+// retryAfter computes the time until the previous window weight drops enough to allow a request.
+func (l *SlidingWindow) retryAfter(s State, now time.Time) time.Duration {
+	windowEnd := s.WindowStart.Add(l.window)
+	if s.PrevCount == 0 || float64(s.Count)+1 > float64(l.limit) {
+		return windowEnd.Sub(now)
+	}
+	// PrevCount*(1-elapsed) + Count + 1 <= limit
+	elapsed := 1 - (float64(l.limit)-float64(s.Count)-1)/float64(s.PrevCount)
+	at := s.WindowStart.Add(time.Duration(elapsed * float64(l.window)))
+	return max(0, at.Sub(now))
+}
+
+// SetHeaders sets RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+// and Retry-After header for the rejected requests.
+// The durations are rounded up to seconds.
+func SetHeaders(h http.Header, res Result) {
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.FormatInt(errbrick.RetryAfterSeconds(res.Reset), 10))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.FormatInt(errbrick.RetryAfterSeconds(res.RetryAfter), 10))
+	}
+}
@@ -0,0 +1,134 @@
+package ratelimitbrick
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Add(d time.Duration) { c.now = c.now.Add(d) }
+
+func newClock() *clock {
+	return &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	clk := newClock()
+	store := NewMemoryStore()
+	store.now = clk.Now
+	l := NewTokenBucket(store, 1, 2)
+	l.now = clk.Now
+	ctx := context.Background()
+
+	res, err := l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, err = l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	res, err = l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	res, err = l.Allow(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "keys are limited independently")
+
+	clk.Add(time.Second)
+	res, err = l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "a token is refilled")
+}
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	clk := newClock()
+	store := NewMemoryStore()
+	store.now = clk.Now
+	l := NewSlidingWindow(store, 4, time.Minute)
+	l.now = clk.Now
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		res, err := l.Allow(ctx, "k")
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		assert.Equal(t, 3-i, res.Remaining)
+	}
+	res, err := l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Minute, res.RetryAfter)
+
+	// in the middle of the next window the previous window weight is 0.5: 4*0.5 = 2 requests are "used"
+	clk.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		res, err = l.Allow(ctx, "k")
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+	res, err = l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 15*time.Second, res.RetryAfter)
+
+	clk.Add(15 * time.Second)
+	res, err = l.Allow(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestSetHeaders(t *testing.T) {
+	h := http.Header{}
+	SetHeaders(h, Result{Limit: 10, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 200 * time.Millisecond})
+	assert.Equal(t, "10", h.Get("RateLimit-Limit"))
+	assert.Equal(t, "0", h.Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", h.Get("RateLimit-Reset"))
+	assert.Equal(t, "1", h.Get("Retry-After"))
+
+	err := Result{RetryAfter: time.Second}.Err()
+	assert.ErrorIs(t, err, errbrick.ErrRateLimited)
+	assert.NoError(t, Result{Allowed: true}.Err())
+}
+
+func TestMemoryStore_Expiration(t *testing.T) {
+	clk := newClock()
+	store := NewMemoryStore()
+	store.now = clk.Now
+	inc := func(s State) State {
+		s.Count++
+		return s
+	}
+
+	s, err := store.Update(context.Background(), "k", time.Second, inc)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), s.Count)
+
+	clk.Add(2 * time.Second)
+	s, err = store.Update(context.Background(), "k", time.Second, inc)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), s.Count, "expired state is reset")
+
+	clk.Add(2 * time.Minute)
+	_, err = store.Update(context.Background(), "other", time.Second, inc)
+	require.NoError(t, err)
+	assert.Len(t, store.entries, 1, "expired entries are swept")
+}
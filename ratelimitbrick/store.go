@@ -0,0 +1,73 @@
+package ratelimitbrick
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// State is a state of the limiter for a key.
+// Token bucket uses Tokens and Updated, sliding window uses Count, PrevCount and WindowStart.
+type State struct {
+	Updated     time.Time
+	WindowStart time.Time
+	Tokens      float64
+	Count       int64
+	PrevCount   int64
+}
+
+// Store stores the limiter states.
+// Implement it to share the limits between the instances (e.g. in Redis).
+type Store interface {
+	// Update atomically applies fn to the state stored by the key and saves the result for ttl.
+	// The zero State is passed to fn if there is no state for the key.
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(State) State) (State, error)
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	state     State
+}
+
+// MemoryStore is an in-memory Store. The expired states are swept periodically on updates.
+type MemoryStore struct {
+	now       func() time.Time
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Update implements Store.
+func (s *MemoryStore) Update(_ context.Context, key string, ttl time.Duration, fn func(State) State) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = memoryEntry{}
+	}
+	entry.state = fn(entry.state)
+	entry.expiresAt = now.Add(ttl)
+	s.entries[key] = entry
+	return entry.state, nil
+}
+
+// sweep removes the expired entries not more often than once a minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package requestidbrick provides the request ID to correlate the logs of a request across the services
+// regardless of tracing (see httpbrick.RequestIDMW, grpcbrick.RequestIDUnaryServerInterceptor
+// and watermillbrick.RequestIDMiddleware).
+package requestidbrick
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/demeero/bricks/otelbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata and watermill message metadata key carrying the request ID.
+	MetadataKey = "x-request-id"
+	// LogKey is the logger attribute key of the request ID.
+	LogKey = "request_id"
+	// AttrKey is the OTEL attribute key of the request ID.
+	AttrKey = attribute.Key("request.id")
+)
+
+// maxLen is the max length of the accepted incoming request ID.
+const maxLen = 128
+
+type ctxKey struct{}
+
+// ToCtx adds the request ID to the context.
+func ToCtx(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromCtx returns the request ID from the context or an empty string.
+func FromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New generates a new request ID (UUID v4).
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether the incoming request ID can be accepted:
+// it must be non-empty, not longer than 128 and contain only printable ASCII characters,
+// so a client can't inject arbitrary data into the logs.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Option is a function that configures Bind.
+type Option func(*bindOpts)
+
+type bindOpts struct {
+	Generator func() string
+}
+
+// WithGenerator sets the function to generate the request ID if the incoming one is missing or invalid.
+// New is used by default.
+func WithGenerator(f func() string) Option {
+	return func(opts *bindOpts) {
+		opts.Generator = f
+	}
+}
+
+// Bind accepts the incoming request ID or generates a new one if it's missing or invalid
+// and binds it to the context: adds it to the context, to the logger in the context (see slogbrick.FromCtx),
+// to the otelbrick context attributes (see otelbrick.AttrsToCtx) and to the current span.
+// The request ID is excluded from the metric attributes (see otelbrick.MetricAttrsFromCtx) to keep their cardinality bounded.
+func Bind(ctx context.Context, incoming string, options ...Option) (context.Context, string) {
+	opts := bindOpts{Generator: New}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	id := incoming
+	if !Valid(id) {
+		id = opts.Generator()
+	}
+	ctx = ToCtx(ctx, id)
+	ctx = slogbrick.ToCtx(ctx, slogbrick.FromCtx(ctx).With(LogKey, id))
+	trace.SpanFromContext(ctx).SetAttributes(AttrKey.String(id))
+	ctx = otelbrick.AttrsToCtx(ctx, []attribute.KeyValue{AttrKey.String(id)})
+	ctx = otelbrick.ExcludeFromMetricsToCtx(ctx, AttrKey)
+	return ctx, id
+}
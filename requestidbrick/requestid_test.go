@@ -0,0 +1,52 @@
+package requestidbrick
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/demeero/bricks/otelbrick"
+	"github.com/demeero/bricks/slogbrick"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: New(), want: true},
+		{name: "custom", id: "req-42_a.b", want: true},
+		{name: "empty", id: "", want: false},
+		{name: "too long", id: strings.Repeat("a", 129), want: false},
+		{name: "space", id: "req 42", want: false},
+		{name: "newline", id: "req\n42", want: false},
+		{name: "non-ascii", id: "запит", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Valid(tt.id))
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := slogbrick.ToCtx(context.Background(), slog.New(slog.NewTextHandler(buf, nil)))
+
+	boundCtx, id := Bind(ctx, "req-42")
+	assert.Equal(t, "req-42", id)
+	assert.Equal(t, "req-42", FromCtx(boundCtx))
+	assert.Equal(t, []attribute.KeyValue{AttrKey.String("req-42")}, otelbrick.AttrsFromCtx(boundCtx))
+	assert.Empty(t, otelbrick.MetricAttrsFromCtx(boundCtx))
+	slogbrick.FromCtx(boundCtx).Info("test")
+	assert.Contains(t, buf.String(), "request_id=req-42")
+
+	_, id = Bind(ctx, "bad id", WithGenerator(func() string { return "generated" }))
+	assert.Equal(t, "generated", id)
+}
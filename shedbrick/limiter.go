@@ -0,0 +1,284 @@
+// Package shedbrick provides a concurrency limiter to shed the load beyond the service capacity.
+package shedbrick
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+// Option is a function that configures the limiter.
+type Option func(*limiterOpts)
+
+type limiterOpts struct {
+	Limit            int
+	MinLimit         int
+	MaxLimit         int
+	LatencyThreshold time.Duration
+	BackoffRatio     float64
+	QueueTimeout     time.Duration
+	RetryAfter       time.Duration
+	Adaptive         bool
+}
+
+// WithLimit sets the (initial, if adaptive) concurrency limit.
+// 100 is used by default.
+func WithLimit(n int) Option {
+	return func(opts *limiterOpts) {
+		opts.Limit = n
+	}
+}
+
+// WithAdaptiveLimit enables AIMD limit adaptation within [minLimit, maxLimit]:
+// the limit is increased by 1/limit for each call faster than the latency threshold while the limiter is utilized
+// (so by ~1 per limit calls) and multiplied by the backoff ratio (0.9 by default) for a slower call.
+// The limit is decreased at most once per round trip: slow calls started before the last decrease are ignored,
+// so a burst of slow calls caused by the same overload backs off once.
+// The initial limit (see WithLimit) is clamped into [minLimit, maxLimit].
+func WithAdaptiveLimit(minLimit, maxLimit int, latencyThreshold time.Duration) Option {
+	return func(opts *limiterOpts) {
+		opts.Adaptive = true
+		opts.MinLimit = minLimit
+		opts.MaxLimit = maxLimit
+		opts.LatencyThreshold = latencyThreshold
+	}
+}
+
+// WithBackoffRatio sets the ratio to decrease the adaptive limit by, it must be in (0, 1).
+func WithBackoffRatio(ratio float64) Option {
+	return func(opts *limiterOpts) {
+		opts.BackoffRatio = ratio
+	}
+}
+
+// WithQueueTimeout sets the max time to wait for a free slot when the limit is reached.
+// 0 rejects the excess calls immediately. 50ms is used by default.
+func WithQueueTimeout(d time.Duration) Option {
+	return func(opts *limiterOpts) {
+		opts.QueueTimeout = d
+	}
+}
+
+// WithRetryAfter sets the retry hint for the rejected calls.
+// 1s is used by default.
+func WithRetryAfter(d time.Duration) Option {
+	return func(opts *limiterOpts) {
+		opts.RetryAfter = d
+	}
+}
+
+// Limiter limits the number of concurrent calls.
+type Limiter struct {
+	lastDecrease time.Time
+	rejected     metric.Int64Counter
+	attrs        metric.MeasurementOption
+	registration metric.Registration
+	waiters      *list.List
+	opts         limiterOpts
+	limit        float64
+	inFlight     int
+	mu           sync.Mutex
+}
+
+// New creates a new Limiter. The name is used in metrics.
+// The limiter exports concurrency_limiter.limit and concurrency_limiter.in_flight gauges
+// and concurrency_limiter.rejected counter.
+// Call Close when the limiter isn't needed anymore to unregister its metrics callback.
+func New(name string, options ...Option) (*Limiter, error) {
+	opts := limiterOpts{
+		Limit:        100,
+		BackoffRatio: 0.9,
+		QueueTimeout: 50 * time.Millisecond,
+		RetryAfter:   time.Second,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Adaptive {
+		opts.Limit = min(opts.MaxLimit, max(opts.MinLimit, opts.Limit))
+	}
+	l := &Limiter{
+		opts:    opts,
+		limit:   float64(opts.Limit),
+		waiters: list.New(),
+		attrs:   metric.WithAttributes(attribute.String("concurrency_limiter.name", name)),
+	}
+	if err := l.initMetrics(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (opts limiterOpts) validate() error {
+	if opts.Limit < 1 {
+		return fmt.Errorf("invalid concurrency limit %d: must be positive", opts.Limit)
+	}
+	if !opts.Adaptive {
+		return nil
+	}
+	if opts.MinLimit < 1 || opts.MinLimit > opts.MaxLimit {
+		return fmt.Errorf("invalid adaptive concurrency limit [%d, %d]: must be 1 <= min <= max", opts.MinLimit, opts.MaxLimit)
+	}
+	if opts.LatencyThreshold <= 0 {
+		return errors.New("invalid adaptive concurrency limit latency threshold: must be positive")
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		return fmt.Errorf("invalid concurrency limit backoff ratio %v: must be in (0, 1)", opts.BackoffRatio)
+	}
+	return nil
+}
+
+func (l *Limiter) initMetrics() error {
+	meter := otel.GetMeterProvider().Meter("bricks/shedbrick")
+	var err error
+	l.rejected, err = meter.Int64Counter("concurrency_limiter.rejected",
+		metric.WithDescription("The number of calls rejected by the concurrency limiter."))
+	if err != nil {
+		return fmt.Errorf("failed create concurrency_limiter.rejected metric: %w", err)
+	}
+	limit, err := meter.Int64ObservableGauge("concurrency_limiter.limit",
+		metric.WithDescription("The current concurrency limit."))
+	if err != nil {
+		return fmt.Errorf("failed create concurrency_limiter.limit metric: %w", err)
+	}
+	inFlight, err := meter.Int64ObservableGauge("concurrency_limiter.in_flight",
+		metric.WithDescription("The number of calls in flight."))
+	if err != nil {
+		return fmt.Errorf("failed create concurrency_limiter.in_flight metric: %w", err)
+	}
+	l.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		curLimit, curInFlight := l.Stats()
+		o.ObserveInt64(limit, int64(curLimit), l.attrs)
+		o.ObserveInt64(inFlight, int64(curInFlight), l.attrs)
+		return nil
+	}, limit, inFlight)
+	if err != nil {
+		return fmt.Errorf("failed register concurrency limiter callback: %w", err)
+	}
+	return nil
+}
+
+// Close unregisters the concurrency_limiter.limit and concurrency_limiter.in_flight metrics callback,
+// so the limiter can be garbage collected. The limiter keeps working after Close, but its gauges aren't reported anymore.
+func (l *Limiter) Close() error {
+	if err := l.registration.Unregister(); err != nil {
+		return fmt.Errorf("failed unregister concurrency limiter callback: %w", err)
+	}
+	return nil
+}
+
+// Stats returns the current limit and the number of calls in flight.
+func (l *Limiter) Stats() (limit, inFlight int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit), l.inFlight
+}
+
+// Permit is an acquired slot. Release it when the call is finished.
+type Permit struct {
+	l     *Limiter
+	start time.Time
+	once  sync.Once
+}
+
+// Release releases the slot and adapts the limit to the call latency (if adaptive).
+func (p *Permit) Release() {
+	p.once.Do(func() {
+		p.l.release(p.start, true)
+	})
+}
+
+// Ignore releases the slot without adapting the limit,
+// e.g. for long-living streams which latency doesn't reflect the load.
+func (p *Permit) Ignore() {
+	p.once.Do(func() {
+		p.l.release(p.start, false)
+	})
+}
+
+// Acquire acquires a slot, waiting for the queue timeout if the limit is reached.
+// If the slot isn't acquired, the error wrapping errbrick.ErrUnavailable with the retry hint is returned.
+func (l *Limiter) Acquire(ctx context.Context) (*Permit, error) {
+	l.mu.Lock()
+	if l.inFlight < int(l.limit) && l.waiters.Len() == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return &Permit{l: l, start: time.Now()}, nil
+	}
+	if l.opts.QueueTimeout <= 0 {
+		l.mu.Unlock()
+		return nil, l.reject(ctx)
+	}
+	ready := make(chan struct{})
+	elem := l.waiters.PushBack(ready)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.opts.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return &Permit{l: l, start: time.Now()}, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	select {
+	case <-ready:
+		// the slot was granted concurrently with the timeout
+		l.mu.Unlock()
+		return &Permit{l: l, start: time.Now()}, nil
+	default:
+		l.waiters.Remove(elem)
+		l.mu.Unlock()
+	}
+	return nil, l.reject(ctx)
+}
+
+func (l *Limiter) reject(ctx context.Context) error {
+	l.rejected.Add(ctx, 1, l.attrs)
+	return errbrick.NewDetailed(errbrick.ErrUnavailable, "server is overloaded", errbrick.WithRetryAfter(l.opts.RetryAfter))
+}
+
+func (l *Limiter) release(start time.Time, observe bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	if observe && l.opts.Adaptive {
+		l.adapt(start, time.Now())
+	}
+	for l.waiters.Len() > 0 && l.inFlight < int(l.limit) {
+		ready, _ := l.waiters.Remove(l.waiters.Front()).(chan struct{})
+		l.inFlight++
+		close(ready)
+	}
+}
+
+func (l *Limiter) adapt(start, now time.Time) {
+	if now.Sub(start) >= l.opts.LatencyThreshold {
+		// the call started before the last decrease was slowed down by the load the limit is already decreased for
+		if start.Before(l.lastDecrease) {
+			return
+		}
+		l.limit = math.Max(float64(l.opts.MinLimit), l.limit*l.opts.BackoffRatio)
+		l.lastDecrease = now
+		return
+	}
+	// increase only if the limiter is utilized, otherwise the limit grows without evidence of capacity
+	if float64(l.inFlight+1) >= l.limit/2 {
+		l.limit = math.Min(float64(l.opts.MaxLimit), l.limit+1/l.limit)
+	}
+}
@@ -0,0 +1,221 @@
+package shedbrick
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/demeero/bricks/errbrick"
+)
+
+func newTestLimiter(t *testing.T, options ...Option) *Limiter {
+	t.Helper()
+	l, err := New("test", options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []Option
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "New_WithDefaults_Succeeds", wantLimit: 100},
+		{name: "New_WithZeroLimit_Fails", options: []Option{WithLimit(0)}, wantErr: true},
+		{name: "New_WithZeroMinLimit_Fails", options: []Option{WithAdaptiveLimit(0, 10, time.Second)}, wantErr: true},
+		{name: "New_WithMinAboveMax_Fails", options: []Option{WithAdaptiveLimit(10, 5, time.Second)}, wantErr: true},
+		{name: "New_WithZeroLatencyThreshold_Fails", options: []Option{WithAdaptiveLimit(1, 10, 0)}, wantErr: true},
+		{
+			name:    "New_WithBackoffRatioOne_Fails",
+			options: []Option{WithAdaptiveLimit(1, 10, time.Second), WithBackoffRatio(1)},
+			wantErr: true,
+		},
+		{
+			name:    "New_WithZeroBackoffRatio_Fails",
+			options: []Option{WithAdaptiveLimit(1, 10, time.Second), WithBackoffRatio(0)},
+			wantErr: true,
+		},
+		{name: "New_WithLimitAboveMax_Clamps", options: []Option{WithAdaptiveLimit(1, 10, time.Second)}, wantLimit: 10},
+		{
+			name:      "New_WithLimitBelowMin_Clamps",
+			options:   []Option{WithLimit(2), WithAdaptiveLimit(5, 10, time.Second)},
+			wantLimit: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New("test", tt.options...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer l.Close()
+			limit, _ := l.Stats()
+			assert.Equal(t, tt.wantLimit, limit)
+		})
+	}
+}
+
+func TestLimiter_RejectsOverLimit(t *testing.T) {
+	l := newTestLimiter(t, WithLimit(2), WithQueueTimeout(0), WithRetryAfter(2*time.Second))
+
+	p1, err := l.Acquire(context.Background())
+	require.NoError(t, err)
+	_, err = l.Acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = l.Acquire(context.Background())
+	require.ErrorIs(t, err, errbrick.ErrUnavailable)
+	detailed, ok := errbrick.AsDetailed(err)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, detailed.RetryAfter)
+
+	p1.Release()
+	p1.Release() // releasing twice is a no-op
+	_, err = l.Acquire(context.Background())
+	require.NoError(t, err)
+	_, inFlight := l.Stats()
+	assert.Equal(t, 2, inFlight)
+}
+
+func TestLimiter_Queue(t *testing.T) {
+	l := newTestLimiter(t, WithLimit(1), WithQueueTimeout(time.Second))
+	p, err := l.Acquire(context.Background())
+	require.NoError(t, err)
+
+	acquired := make(chan error)
+	go func() {
+		_, err := l.Acquire(context.Background())
+		acquired <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	p.Release()
+	require.NoError(t, <-acquired)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx)
+	require.ErrorIs(t, err, errbrick.ErrUnavailable)
+	_, inFlight := l.Stats()
+	assert.Equal(t, 1, inFlight)
+}
+
+func TestLimiter_Adaptive(t *testing.T) {
+	l := newTestLimiter(t, WithLimit(10), WithAdaptiveLimit(5, 8, 100*time.Millisecond), WithBackoffRatio(0.5))
+
+	// slow calls decrease the limit down to min
+	for i := 0; i < 3; i++ {
+		p, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		p.start = p.start.Add(-time.Second)
+		p.Release()
+	}
+	limit, _ := l.Stats()
+	assert.Equal(t, 5, limit)
+
+	// fast calls of the utilized limiter increase the limit up to max
+	var permits []*Permit
+	for i := 0; i < 4; i++ {
+		p, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		permits = append(permits, p)
+	}
+	for i := 0; i < 100; i++ {
+		p, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		p.Release()
+	}
+	limit, _ = l.Stats()
+	assert.Equal(t, 8, limit)
+
+	// ignored calls don't adapt the limit
+	for _, p := range permits {
+		p.start = p.start.Add(-time.Second)
+		p.Ignore()
+	}
+	limit, _ = l.Stats()
+	assert.Equal(t, 8, limit)
+}
+
+func TestLimiter_AdaptiveSlowBurst(t *testing.T) {
+	l := newTestLimiter(t, WithLimit(16), WithAdaptiveLimit(2, 16, 5*time.Millisecond),
+		WithBackoffRatio(0.5), WithQueueTimeout(0))
+
+	// a burst of concurrent slow calls decreases the limit once
+	var permits []*Permit
+	for i := 0; i < 16; i++ {
+		p, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		permits = append(permits, p)
+	}
+	time.Sleep(10 * time.Millisecond)
+	for _, p := range permits {
+		p.Release()
+	}
+	limit, _ := l.Stats()
+	assert.Equal(t, 8, limit)
+
+	// sequential slow calls decrease the limit down to min, but not below
+	for i := 0; i < 5; i++ {
+		p, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		p.Release()
+	}
+	limit, _ = l.Stats()
+	assert.Equal(t, 2, limit)
+
+	// fast calls of the utilized limiter recover the limit up to max
+	for i := 0; i < 200; i++ {
+		permits = permits[:0]
+		for {
+			p, err := l.Acquire(context.Background())
+			if err != nil {
+				break
+			}
+			permits = append(permits, p)
+		}
+		for _, p := range permits {
+			p.Release()
+		}
+	}
+	limit, _ = l.Stats()
+	assert.Equal(t, 16, limit)
+}
+
+func TestLimiter_Close(t *testing.T) {
+	prevProvider := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(prevProvider) })
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	l, err := New("test")
+	require.NoError(t, err)
+	collectGauges := func() []string {
+		rm := metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		var names []string
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == "concurrency_limiter.limit" || m.Name == "concurrency_limiter.in_flight" {
+					names = append(names, m.Name)
+				}
+			}
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"concurrency_limiter.limit", "concurrency_limiter.in_flight"}, collectGauges())
+	require.NoError(t, l.Close())
+	assert.Empty(t, collectGauges())
+}
@@ -0,0 +1,29 @@
+package watermillbrick
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/demeero/bricks/healthbrick"
+)
+
+// HealthCheckMetadataKey is the message metadata key set for the messages published by PublisherChecker.
+// Consumers of the topic should skip such messages.
+const HealthCheckMetadataKey = "health_check"
+
+// PublisherChecker returns healthbrick.Checker that publishes a message marked by HealthCheckMetadataKey to the topic.
+// Use a dedicated topic to not bother the regular consumers.
+func PublisherChecker(pub message.Publisher, topic string) healthbrick.Checker {
+	return func(ctx context.Context) error {
+		msg := message.NewMessage(watermill.NewUUID(), nil)
+		msg.SetContext(ctx)
+		msg.Metadata.Set(HealthCheckMetadataKey, "true")
+		if err := pub.Publish(topic, msg); err != nil {
+			return fmt.Errorf("failed publish health check message: %w", err)
+		}
+		return nil
+	}
+}
@@ -46,6 +46,9 @@ func (p *OTELPublisher) Publish(topic string, messages ...*message.Message) erro
 	if len(messages) == 0 {
 		return nil
 	}
+	for _, msg := range messages {
+		setRequestID(msg)
+	}
 	ctx := messages[0].Context()
 	if p.cfg.NewRootSpanWithLink {
 		spanCtx, span := otel.GetTracerProvider().Tracer("bricks/publisher").
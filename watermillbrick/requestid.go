@@ -0,0 +1,31 @@
+package watermillbrick
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/demeero/bricks/requestidbrick"
+)
+
+// RequestIDMiddleware is a handler middleware that binds the request ID from the message metadata
+// (see requestidbrick.MetadataKey and OTELPublisher) to the message context and the logger,
+// so the consumer logs share the request ID with the producer.
+// A new request ID is generated if the message has none.
+func RequestIDMiddleware(options ...requestidbrick.Option) message.HandlerMiddleware {
+	return func(h message.HandlerFunc) message.HandlerFunc {
+		return func(msg *message.Message) ([]*message.Message, error) {
+			ctx, _ := requestidbrick.Bind(msg.Context(), msg.Metadata.Get(requestidbrick.MetadataKey), options...)
+			msg.SetContext(ctx)
+			return h(msg)
+		}
+	}
+}
+
+// setRequestID sets the request ID from the message context to the message metadata if it isn't set yet.
+func setRequestID(msg *message.Message) {
+	if msg.Metadata.Get(requestidbrick.MetadataKey) != "" {
+		return
+	}
+	if id := requestidbrick.FromCtx(msg.Context()); id != "" {
+		msg.Metadata.Set(requestidbrick.MetadataKey, id)
+	}
+}